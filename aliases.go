@@ -0,0 +1,533 @@
+package bible
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/ButbkaDrug/bible/internal/repository"
+)
+
+// AliasTable lists names and abbreviations of books by MyBible book
+// number. Names from any table work with any module, so `Иоанна 3:16`
+// can be read from the ESV and `John 3:16` from the Synodal.
+type AliasTable map[int][]string
+
+// names that are neither in defBooks nor SBL abbreviations
+var EnglishNames = AliasTable{
+	10:  {"Gn"},
+	20:  {"Ex"},
+	50:  {"Dt"},
+	60:  {"Jos"},
+	70:  {"Jdg", "Jdgs"},
+	190: {"Est"},
+	220: {"Jb"},
+	230: {"Psalms", "Psa"},
+	240: {"Pr"},
+	250: {"Ecc", "Eccles", "Qoheleth"},
+	260: {"Song of Songs", "Canticles", "Song of Sol"},
+	270: {"Wisdom of Solomon"},
+	280: {"Ecclesiasticus"},
+	330: {"Eze"},
+	340: {"Dn"},
+	470: {"Mt"},
+	480: {"Mk", "Mrk"},
+	490: {"Lk"},
+	500: {"Jn", "Jhn"},
+	510: {"Acts of the Apostles", "Ac"},
+	520: {"Ro"},
+	570: {"Php"},
+	640: {"Philem", "Phm"},
+	660: {"Jm"},
+	730: {"Revelations", "Apocalypse"},
+}
+
+// abbreviations of the SBL Handbook of Style
+var SBLAbbreviations = AliasTable{
+	10:  {"Gen"},
+	20:  {"Exod"},
+	30:  {"Lev"},
+	40:  {"Num"},
+	50:  {"Deut"},
+	60:  {"Josh"},
+	70:  {"Judg"},
+	80:  {"Ruth"},
+	90:  {"1 Sam"},
+	100: {"2 Sam"},
+	110: {"1 Kgs"},
+	120: {"2 Kgs"},
+	130: {"1 Chr"},
+	140: {"2 Chr"},
+	150: {"Ezra"},
+	160: {"Neh"},
+	165: {"1 Esd"},
+	170: {"Tob"},
+	180: {"Jdt"},
+	190: {"Esth"},
+	192: {"Add Esth", "Gk Esth"},
+	220: {"Job"},
+	230: {"Ps", "Pss"},
+	240: {"Prov"},
+	250: {"Eccl", "Qoh"},
+	260: {"Song", "Cant"},
+	270: {"Wis"},
+	280: {"Sir"},
+	290: {"Isa"},
+	300: {"Jer"},
+	305: {"Pr Azar"},
+	310: {"Lam"},
+	315: {"Ep Jer"},
+	320: {"Bar"},
+	323: {"Sg Three"},
+	325: {"Sus"},
+	330: {"Ezek"},
+	340: {"Dan"},
+	345: {"Bel"},
+	350: {"Hos"},
+	360: {"Joel"},
+	370: {"Amos"},
+	380: {"Obad"},
+	390: {"Jonah"},
+	400: {"Mic"},
+	410: {"Nah"},
+	420: {"Hab"},
+	430: {"Zeph"},
+	440: {"Hag"},
+	450: {"Zech"},
+	460: {"Mal"},
+	462: {"1 Macc"},
+	464: {"2 Macc"},
+	466: {"3 Macc"},
+	467: {"4 Macc"},
+	468: {"2 Esd"},
+	470: {"Matt"},
+	480: {"Mark"},
+	490: {"Luke"},
+	500: {"John"},
+	510: {"Acts"},
+	520: {"Rom"},
+	530: {"1 Cor"},
+	540: {"2 Cor"},
+	550: {"Gal"},
+	560: {"Eph"},
+	570: {"Phil"},
+	580: {"Col"},
+	590: {"1 Thess"},
+	600: {"2 Thess"},
+	610: {"1 Tim"},
+	620: {"2 Tim"},
+	630: {"Titus"},
+	640: {"Phlm"},
+	650: {"Heb"},
+	660: {"Jas"},
+	670: {"1 Pet"},
+	680: {"2 Pet"},
+	690: {"1 John"},
+	700: {"2 John"},
+	710: {"3 John"},
+	720: {"Jude"},
+	730: {"Rev"},
+	790: {"Pr Man"},
+}
+
+var RussianNames = AliasTable{
+	10:  {"Бытие", "Быт"},
+	20:  {"Исход", "Исх"},
+	30:  {"Левит", "Лев"},
+	40:  {"Числа", "Чис"},
+	50:  {"Второзаконие", "Втор"},
+	60:  {"Иисус Навин", "Иисуса Навина", "Нав"},
+	70:  {"Судьи", "Суд"},
+	80:  {"Руфь", "Руф"},
+	90:  {"1 Царств", "1Цар"},
+	100: {"2 Царств", "2Цар"},
+	110: {"3 Царств", "3Цар"},
+	120: {"4 Царств", "4Цар"},
+	130: {"1 Паралипоменон", "1Пар"},
+	140: {"2 Паралипоменон", "2Пар"},
+	150: {"Ездра", "Ездры", "Езд"},
+	160: {"Неемия", "Неем"},
+	165: {"2 Ездры", "2Езд"},
+	170: {"Товит", "Тов"},
+	180: {"Иудифь", "Иудф"},
+	190: {"Есфирь", "Есф"},
+	220: {"Иов"},
+	230: {"Псалтирь", "Псалом", "Псалмы", "Пс"},
+	240: {"Притчи", "Прит"},
+	250: {"Екклесиаст", "Еккл"},
+	260: {"Песнь Песней", "Песн"},
+	270: {"Премудрость Соломона", "Прем"},
+	280: {"Сирах", "Премудрость Сираха", "Сир"},
+	290: {"Исаия", "Ис"},
+	300: {"Иеремия", "Иер"},
+	310: {"Плач Иеремии", "Плач"},
+	315: {"Послание Иеремии", "Посл Иер"},
+	320: {"Варух", "Вар"},
+	330: {"Иезекииль", "Иез"},
+	340: {"Даниил", "Дан"},
+	350: {"Осия", "Ос"},
+	360: {"Иоиль", "Иоил"},
+	370: {"Амос", "Ам"},
+	380: {"Авдий", "Авд"},
+	390: {"Иона", "Ион"},
+	400: {"Михей", "Мих"},
+	410: {"Наум"},
+	420: {"Аввакум", "Авв"},
+	430: {"Софония", "Соф"},
+	440: {"Аггей", "Агг"},
+	450: {"Захария", "Зах"},
+	460: {"Малахия", "Мал"},
+	462: {"1 Маккавейская", "1Мак"},
+	464: {"2 Маккавейская", "2Мак"},
+	466: {"3 Маккавейская", "3Мак"},
+	468: {"3 Ездры", "3Езд"},
+	470: {"Матфея", "Матфей", "От Матфея", "Мф"},
+	480: {"Марка", "Марк", "От Марка", "Мк"},
+	490: {"Луки", "Лука", "От Луки", "Лк"},
+	500: {"Иоанна", "Иоанн", "От Иоанна", "Ин"},
+	510: {"Деяния", "Деяния Апостолов", "Деян"},
+	520: {"Римлянам", "Рим"},
+	530: {"1 Коринфянам", "1Кор"},
+	540: {"2 Коринфянам", "2Кор"},
+	550: {"Галатам", "Гал"},
+	560: {"Ефесянам", "Еф"},
+	570: {"Филиппийцам", "Флп"},
+	580: {"Колоссянам", "Кол"},
+	590: {"1 Фессалоникийцам", "1Фес"},
+	600: {"2 Фессалоникийцам", "2Фес"},
+	610: {"1 Тимофею", "1Тим"},
+	620: {"2 Тимофею", "2Тим"},
+	630: {"Титу", "Тит"},
+	640: {"Филимону", "Флм"},
+	650: {"Евреям", "Евр"},
+	660: {"Иакова", "Иак"},
+	670: {"1 Петра", "1Пет"},
+	680: {"2 Петра", "2Пет"},
+	690: {"1 Иоанна", "1Ин"},
+	700: {"2 Иоанна", "2Ин"},
+	710: {"3 Иоанна", "3Ин"},
+	720: {"Иуды", "Иуд"},
+	730: {"Откровение", "Апокалипсис", "Откр"},
+}
+
+// `1 Царів` are Kings here and Samuel in Russian, so the short `1Цар`
+// is left to the Russian table
+var UkrainianNames = AliasTable{
+	10:  {"Буття", "Бут"},
+	20:  {"Вихід", "Вих"},
+	30:  {"Левит", "Лев"},
+	40:  {"Числа", "Чис"},
+	50:  {"Повторення Закону", "Повт"},
+	60:  {"Ісус Навин", "Ісуса Навина", "Іс Нав"},
+	70:  {"Судді", "Суд"},
+	80:  {"Рут"},
+	90:  {"1 Самуїлова", "1Сам"},
+	100: {"2 Самуїлова", "2Сам"},
+	110: {"1 Царів"},
+	120: {"2 Царів"},
+	130: {"1 Хронік", "1Хр"},
+	140: {"2 Хронік", "2Хр"},
+	150: {"Ездри"},
+	160: {"Неемія", "Неем"},
+	190: {"Естер", "Ест"},
+	220: {"Йов"},
+	230: {"Псалми", "Псалтир", "Пс"},
+	240: {"Приповісті", "Прип"},
+	250: {"Екклезіяст", "Проповідник", "Екл"},
+	260: {"Пісня над піснями", "Пісня пісень", "Пісн"},
+	290: {"Ісая", "Ісаї", "Іс"},
+	300: {"Єремія", "Єремії", "Єр"},
+	310: {"Плач Єремії", "Плач"},
+	330: {"Єзекіїль", "Єзекіїля", "Єз"},
+	340: {"Даниїл", "Даниїла", "Дан"},
+	350: {"Осія", "Ос"},
+	360: {"Йоіл", "Йоіла"},
+	370: {"Амос", "Ам"},
+	380: {"Овдій", "Овд"},
+	390: {"Йона", "Йони"},
+	400: {"Михей", "Мих"},
+	410: {"Наум"},
+	420: {"Авакум", "Авак"},
+	430: {"Софонія", "Соф"},
+	440: {"Огій", "Ог"},
+	450: {"Захарія", "Зах"},
+	460: {"Малахія", "Малахії", "Мал"},
+	470: {"Матвія", "Матвій", "Від Матвія", "Мт"},
+	480: {"Марка", "Марко", "Від Марка", "Мр"},
+	490: {"Луки", "Лука", "Від Луки", "Лк"},
+	500: {"Івана", "Іван", "Від Івана", "Ів"},
+	510: {"Дії", "Дії Апостолів"},
+	520: {"Римлян", "Рим"},
+	530: {"1 Коринтян", "1Кор"},
+	540: {"2 Коринтян", "2Кор"},
+	550: {"Галатів", "Гал"},
+	560: {"Ефесян", "Еф"},
+	570: {"Филип'ян", "Флп"},
+	580: {"Колоссян", "Кол"},
+	590: {"1 Солунян", "1Сол"},
+	600: {"2 Солунян", "2Сол"},
+	610: {"1 Тимофія", "1Тим"},
+	620: {"2 Тимофія", "2Тим"},
+	630: {"Тита", "Тит"},
+	640: {"Филимона", "Флм"},
+	650: {"Євреїв", "Євр"},
+	660: {"Якова", "Як"},
+	670: {"1 Петра", "1Пет"},
+	680: {"2 Петра", "2Пет"},
+	690: {"1 Івана", "1Ів"},
+	700: {"2 Івана", "2Ів"},
+	710: {"3 Івана", "3Ів"},
+	720: {"Юди", "Юд"},
+	730: {"Об'явлення", "Одкровення", "Об"},
+}
+
+// names of defBooks and the shipped tables. When two tables give the
+// same name to different books the first one wins
+var defAliases = newAliasIndex(
+	booksTable(defBooks),
+	SBLAbbreviations,
+	EnglishNames,
+	RussianNames,
+	UkrainianNames,
+)
+
+type aliasIndex map[string]int
+
+func newAliasIndex(tables ...AliasTable) aliasIndex {
+	var index = make(aliasIndex)
+
+	for _, t := range tables {
+		index.add(t)
+	}
+
+	return index
+}
+
+func (index aliasIndex) add(t AliasTable) {
+	for number, names := range t {
+		for _, name := range names {
+			key := aliasKey(name)
+
+			if _, ok := index[key]; !ok && key != "" {
+				index[key] = number
+			}
+		}
+	}
+}
+
+func (index aliasIndex) lookup(name string) (int, bool) {
+	number, ok := index[aliasKey(name)]
+	return number, ok
+}
+
+func booksTable(books []repository.Book) AliasTable {
+	var t = make(AliasTable, len(books))
+
+	for _, b := range books {
+		n := int(b.BookNumber)
+		t[n] = append(t[n], b.LongName, b.ShortName)
+	}
+
+	return t
+}
+
+// names are compared without case, spaces, dots and apostrophes, so
+// `1 Jn.`, `1jn` and `1 JN` are the same
+func aliasKey(name string) string {
+	return strings.Map(func(r rune) rune {
+		switch r {
+		case ' ', '\t', '.', '\'', '’':
+			return -1
+		}
+		return r
+	}, strings.ToLower(name))
+}
+
+// adds names of books on top of the shipped tables. Names given here
+// win over names from the tables but not over the module's own names
+func (app *Bible) AddAliasTable(t AliasTable) *Bible {
+	if app.bookAliases == nil {
+		app.bookAliases = make(aliasIndex)
+	}
+
+	app.bookAliases.add(t)
+	app.aliasTables = append(app.aliasTables, t)
+
+	return app
+}
+
+// most books suggested in an error
+const MAX_SUGGESTIONS = 5
+
+// shorter beginnings of names are not guessed
+const MIN_PREFIX = 2
+
+// BookError is returned when a name matches no book or more than one
+type BookError struct {
+	Name string
+	// true when the name fits several books equally well
+	Ambiguous bool
+	// names of the books that are close to Name
+	Suggestions []string
+}
+
+func (e *BookError) Error() string {
+	msg := fmt.Sprintf("unknown book `%s`", e.Name)
+	if e.Ambiguous {
+		msg = fmt.Sprintf("book `%s` is ambiguous", e.Name)
+	}
+
+	if len(e.Suggestions) > 0 {
+		msg += ", did you mean " + orList(e.Suggestions) + "?"
+	}
+
+	return msg
+}
+
+// will look the name up exactly, then as the beginning of a name and
+// then as a misspelled name. Only books of the module are guessed
+func (app *Bible) findBook(name string) (int, error) {
+	if number := int(app.getBookNumber(name)); number != 0 {
+		return number, nil
+	}
+
+	key := aliasKey(name)
+	names := app.bookNames()
+
+	var books []int
+
+	if utf8.RuneCountInString(key) >= MIN_PREFIX {
+		for k, number := range names {
+			if strings.HasPrefix(k, key) && !slices.Contains(books, number) {
+				books = append(books, number)
+			}
+		}
+	}
+
+	if len(books) == 1 {
+		return books[0], nil
+	}
+
+	if len(books) > 1 {
+		return 0, app.bookError(name, books, true)
+	}
+
+	// books further than the limit are neither guessed nor suggested
+	limit := typoLimit(key)
+	best := limit
+
+	for k, number := range names {
+		d := distance(key, k)
+
+		switch {
+		case d > limit:
+			continue
+		case d < best:
+			best = d
+			books = []int{number}
+		case d == best && !slices.Contains(books, number):
+			books = append(books, number)
+		}
+	}
+
+	if len(books) == 1 {
+		return books[0], nil
+	}
+
+	return 0, app.bookError(name, books, len(books) > 1)
+}
+
+// names of the books the module has, with the module's own names first
+func (app *Bible) bookNames() aliasIndex {
+	var index = make(aliasIndex)
+	var has = make(map[int]bool, len(app.books))
+
+	// books outside of the canon are only found by their exact names
+	var books []repository.Book
+	for _, b := range app.books {
+		if app.canon.Has(int(b.BookNumber)) {
+			has[int(b.BookNumber)] = true
+			books = append(books, b)
+		}
+	}
+
+	index.add(booksTable(books))
+
+	for _, names := range []aliasIndex{app.bookAliases, defAliases} {
+		for key, number := range names {
+			if _, ok := index[key]; !ok && has[number] {
+				index[key] = number
+			}
+		}
+	}
+
+	return index
+}
+
+func (app *Bible) bookError(name string, books []int, ambiguous bool) *BookError {
+	slices.Sort(books)
+
+	if len(books) > MAX_SUGGESTIONS {
+		books = books[:MAX_SUGGESTIONS]
+	}
+
+	var suggestions = make([]string, len(books))
+	for i, number := range books {
+		suggestions[i] = app.getBookName(float64(number))
+	}
+
+	return &BookError{Name: name, Ambiguous: ambiguous, Suggestions: suggestions}
+}
+
+// how many typos are corrected without asking. Short names are too
+// close to each other to guess
+func typoLimit(key string) int {
+	n := utf8.RuneCountInString(key)
+	if n < 3 {
+		return 0
+	}
+
+	return min(max(n/4, 1), 3)
+}
+
+// number of edits between a and b, swapping two letters is one edit
+func distance(a, b string) int {
+	s, t := []rune(a), []rune(b)
+
+	var d = make([][]int, len(s)+1)
+	for i := range d {
+		d[i] = make([]int, len(t)+1)
+		d[i][0] = i
+	}
+
+	for j := range d[0] {
+		d[0][j] = j
+	}
+
+	for i := 1; i <= len(s); i++ {
+		for j := 1; j <= len(t); j++ {
+			cost := 1
+			if s[i-1] == t[j-1] {
+				cost = 0
+			}
+
+			d[i][j] = min(d[i-1][j]+1, d[i][j-1]+1, d[i-1][j-1]+cost)
+
+			if i > 1 && j > 1 && s[i-1] == t[j-2] && s[i-2] == t[j-1] {
+				d[i][j] = min(d[i][j], d[i-2][j-2]+1)
+			}
+		}
+	}
+
+	return d[len(s)][len(t)]
+}
+
+// `a`, `a or b`, `a, b or c`
+func orList(items []string) string {
+	if len(items) < 2 {
+		return strings.Join(items, "")
+	}
+
+	return strings.Join(items[:len(items)-1], ", ") + " or " + items[len(items)-1]
+}
@@ -0,0 +1,167 @@
+package bible
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestLookupBookAliases(t *testing.T) {
+	app := testModule(t).AddAliasTable(AliasTable{
+		500: {"Йн"},
+		// module names win over added ones
+		510: {"Jn"},
+	})
+
+	tests := []string{
+		"John",
+		"jn",
+		"Иоанна",
+		"ин.",
+		"Івана",
+		"Деяния",
+		"Дії",
+		"Acts of the Apostles",
+		"Йн",
+		"1 Jn",
+		"1jn",
+		"Об'явлення",
+		"Обявлення",
+		"I Jn",
+		"First John",
+		"1st Jn.",
+		"1-е Иоанна",
+		"1 Ин.",
+		"Hezekiah",
+	}
+
+	expectedResults := []int{500, 500, 500, 500, 500, 510, 510, 510, 500, 690, 690, 730, 730, 690, 690, 690, 690, 690, 0}
+
+	for i, test := range tests {
+		if result, _ := app.LookupBook(test); result != expectedResults[i] {
+			t.Fatalf("TEST[%d] failed: %s expected %d got %d", i, test, expectedResults[i], result)
+		}
+	}
+}
+
+func TestResolveLocalized(t *testing.T) {
+	app := testModule(t)
+
+	tests := []string{
+		"Иоанна 20:2",
+		"Від Івана 21:1-2",
+		"Деян 1:1, 3",
+	}
+
+	expectedResults := []string{
+		"[John 20:2]",
+		"[John 21:1-2]",
+		"[Acts 1:1 Acts 1:3]",
+	}
+
+	for i, test := range tests {
+		passages, err := app.Resolve(test)
+		if err != nil {
+			t.Fatalf("TEST[%d] failed: %s", i, err)
+		}
+
+		if result := fmt.Sprint(passages); result != expectedResults[i] {
+			t.Fatalf("TEST[%d] failed: expected %s got %s", i, expectedResults[i], result)
+		}
+	}
+}
+
+func TestFindBook(t *testing.T) {
+	app := testModule(t)
+
+	tests := []string{
+		"Jhon",
+		"Act",
+		"Actz",
+		"jo",
+		"Acts of the Apostels",
+	}
+
+	expectedResults := []int{500, 510, 510, 500, 510}
+
+	for i, test := range tests {
+		result, err := app.findBook(test)
+		if err != nil || result != expectedResults[i] {
+			t.Fatalf("TEST[%d] failed: %s expected %d got %d %v", i, test, expectedResults[i], result, err)
+		}
+	}
+
+	// `Ecc` begins Ecclesiasticus too, so it has to be a name
+	app = testModule(t,
+		`INSERT INTO books VALUES (250, 'Eccl', 'Ecclesiastes', '#ffffff')`,
+		`INSERT INTO books VALUES (280, 'Sir', 'Sirach', '#ffffff')`,
+	)
+
+	for i, test := range []string{"Ecc", "Eccl"} {
+		result, err := app.findBook(test)
+		if err != nil || result != 250 {
+			t.Fatalf("TEST[%d] failed: %s expected 250 got %d %v", len(tests)+i, test, result, err)
+		}
+	}
+}
+
+func TestFindBookErrors(t *testing.T) {
+	app := testModule(t).AddAliasTable(AliasTable{510: {"Jonas"}})
+
+	tests := []string{
+		"Jo",
+		"Abcs",
+		"xyz",
+		"Xyzzy",
+		// not in the module, so not guessed
+		"Phillipians",
+	}
+
+	expectedResults := []string{
+		"book `Jo` is ambiguous, did you mean John or Acts?",
+		// two typos are too many for four letters
+		"unknown book `Abcs`",
+		"unknown book `xyz`",
+		"unknown book `Xyzzy`",
+		"unknown book `Phillipians`",
+	}
+
+	for i, test := range tests {
+		_, err := app.findBook(test)
+
+		var bookErr *BookError
+		if !errors.As(err, &bookErr) {
+			t.Fatalf("TEST[%d] failed: expected BookError got %v", i, err)
+		}
+
+		if bookErr.Error() != expectedResults[i] {
+			t.Fatalf("TEST[%d] failed: expected %q got %q", i, expectedResults[i], bookErr.Error())
+		}
+	}
+}
+
+func TestNameOnly(t *testing.T) {
+	app := testModule(t,
+		`INSERT INTO books VALUES (570, 'Phil', 'Philippians', '#ffffff')`,
+		`INSERT INTO books VALUES (640, 'Phlm', 'Philemon', '#ffffff')`,
+		`INSERT INTO verses VALUES (570, 1, 1, 'grace')`,
+		`INSERT INTO verses VALUES (640, 1, 1, 'grace')`,
+	)
+
+	// names alone are guessed like names with numbers
+	verses, err := app.SetQuery("Phillipians").Execute()
+	if err != nil || len(verses) != 1 || verses[0].BookNumber != 570 {
+		t.Fatalf("TEST[0] failed: expected chapters of Philippians, got %v %v", verses, err)
+	}
+
+	_, err = app.SetQuery("Ph").Execute()
+	if err == nil || err.Error() != "book `Ph` is ambiguous, did you mean Philippians or Philemon?" {
+		t.Fatalf("TEST[1] failed: expected ambiguous book, got %v", err)
+	}
+
+	// nothing is close to the words, so they are searched for
+	verses, err = app.SetQuery("grace").Execute()
+	if err != nil || len(verses) != 2 {
+		t.Fatalf("TEST[2] failed: expected search results, got %v %v", verses, err)
+	}
+}
@@ -0,0 +1,70 @@
+package bible
+
+import "context"
+
+// Annotation is something the reader put on a passage: a highlight, a
+// note or both
+type Annotation struct {
+	Passage Passage
+	// style of the highlight, e.g. `yellow` or `black on yellow`
+	Highlight string
+	Note      string
+}
+
+// Annotations are kept by the reader outside of the modules. Passages
+// are translation independent, so they show up in every translation
+type Annotations interface {
+	// BookAnnotations returns annotations of passages that have verses
+	// of the book, older ones first
+	BookAnnotations(ctx context.Context, book int) ([]Annotation, error)
+}
+
+// when set Execute attaches highlights and notes of the reader to the
+// verses. nil turns them off
+func (app *Bible) SetAnnotations(a Annotations) *Bible {
+	app.annotations = a
+	return app
+}
+
+// looks annotations up once for every book in verses. Later highlights
+// win over older ones
+func (app *Bible) attachAnnotations(verses []Verse) error {
+	var book = -1
+	var annotations []Annotation
+
+	for i, v := range verses {
+		// list of books has no annotations
+		if v.Chapter < 1 {
+			continue
+		}
+
+		if v.BookNumber != book {
+			var err error
+
+			book = v.BookNumber
+			if annotations, err = app.annotations.BookAnnotations(app.ctx, book); err != nil {
+				return err
+			}
+		}
+
+		addAnnotations(&verses[i], annotations)
+	}
+
+	return nil
+}
+
+func addAnnotations(v *Verse, annotations []Annotation) {
+	for _, a := range annotations {
+		if !a.Passage.Contains(v.Reference()) {
+			continue
+		}
+
+		if a.Highlight != "" {
+			v.Highlight = a.Highlight
+		}
+
+		if a.Note != "" {
+			v.Notes = append(v.Notes, a.Note)
+		}
+	}
+}
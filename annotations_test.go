@@ -0,0 +1,90 @@
+package bible
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"testing"
+)
+
+// annotations kept in memory, filtered by book the way a store would
+type testAnnotations []Annotation
+
+func (a testAnnotations) BookAnnotations(ctx context.Context, book int) ([]Annotation, error) {
+	var result []Annotation
+
+	for _, an := range a {
+		if an.Passage.Start.BookNumber <= book && book <= an.Passage.End.BookNumber {
+			result = append(result, an)
+		}
+	}
+
+	return result, nil
+}
+
+func TestAttachAnnotations(t *testing.T) {
+	app := testModule(t)
+
+	john20 := Reference{BookNumber: 500, Chapter: 20}
+	app.SetAnnotations(testAnnotations{
+		{Passage: Passage{Start: Reference{500, 20, 2}, End: Reference{510, 1, 1}}, Highlight: "yellow"},
+		{Passage: NewPassage(john20), Note: "chapter"},
+		{Passage: NewPassage(Reference{500, 20, 3}), Highlight: "green", Note: "verse"},
+	})
+
+	verses, err := app.SetQuery("John 20:1-Acts 1:2").Execute()
+	if err != nil {
+		t.Fatalf("execute failed: %s", err)
+	}
+
+	expectedResults := []string{
+		"John 20:1  [chapter]",
+		"John 20:2 yellow [chapter]",
+		"John 20:3 green [chapter verse]",
+		"John 21:1 yellow []",
+		"John 21:2 yellow []",
+		"Acts 1:1 yellow []",
+		"Acts 1:2  []",
+	}
+
+	if len(verses) != len(expectedResults) {
+		t.Fatalf("expected %d verses got %d", len(expectedResults), len(verses))
+	}
+
+	for i, v := range verses {
+		result := fmt.Sprintf("%s %s %v", v.Reference(), v.Highlight, v.Notes)
+
+		if result != expectedResults[i] {
+			t.Fatalf("TEST[%d] failed: expected %q, got %q", i, expectedResults[i], result)
+		}
+	}
+}
+
+func TestRenderAnnotations(t *testing.T) {
+	verses := []Verse{
+		{Book: "John", BookNumber: 500, Chapter: 3, Verse: 16, Text: "loved", Highlight: "yellow", Notes: []string{"note"}},
+		{Book: "John", BookNumber: 500, Chapter: 3, Verse: 17, Text: "sent", Highlight: "nonsense"},
+	}
+
+	tests := []*defaultRender{
+		NewDefaultRender().SetWidth(-1),
+		NewDefaultRender().SetWidth(-1).SetColorMode(COLOR_16),
+	}
+
+	expectedResults := []string{
+		"John 3:16-17\n¹⁶loved ✎ ¹⁷sent\n",
+		"\033[1;32mJohn 3:16-17\033[0m\n\033[33m¹⁶loved\033[0m \033[35m✎\033[0m ¹⁷sent\n",
+	}
+
+	for i, r := range tests {
+		var result bytes.Buffer
+
+		if err := r.Render(&result, verses); err != nil {
+			t.Fatalf("TEST[%d] failed: %s", i, err)
+		}
+
+		if result.String() != expectedResults[i] {
+			t.Fatalf("TEST[%d] failed: expected %q, got %q", i, expectedResults[i], result.String())
+		}
+	}
+}
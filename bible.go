@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"io"
 	"log"
+	"math"
 	"os"
 	"strings"
 
@@ -22,16 +23,26 @@ type Verse struct {
 	BookNumber int
 	Chapter    int
 	Verse      int
+	// section heading that goes before the verse, if any
+	Heading string
+	// verse was not requested, it is shown around the ones that were
+	Context bool
+	// style of the reader's highlight, if any
+	Highlight string
+	// notes of the reader on the verse
+	Notes []string
 }
 
-func wrapBooks(books []repository.Book) []Verse {
+// books of an empty query go to the renderer as verses without
+// chapters. Books returns the catalog itself
+func wrapBooks(books []Book) []Verse {
 	var result = make([]Verse, len(books))
 
 	for i, b := range books {
 		result[i].Book = b.LongName
 		result[i].Text = b.ShortName
 
-		result[i].BookNumber = int(b.BookNumber)
+		result[i].BookNumber = b.Number
 		result[i].Chapter = 0
 		result[i].Verse = 0
 	}
@@ -67,15 +78,29 @@ type Renderer interface {
 }
 
 type Bible struct {
-	ctx            context.Context
-	db             *repository.Queries
-	render         Renderer
-	writer         io.Writer
-	books          []repository.Book
-	defBookNumbers []repository.Book
-
-	query string
-	env   string
+	ctx    context.Context
+	db     *repository.Queries
+	render Renderer
+	writer io.Writer
+	books  []repository.Book
+
+	query    string
+	env      string
+	limit    int
+	before   int
+	after    int
+	headings bool
+	// book name alone reads the book instead of listing chapters
+	wholeBooks bool
+	aliases    map[string]string
+	// names from AddAliasTable
+	bookAliases aliasIndex
+	aliasTables []AliasTable
+	canon       Canon
+	annotations Annotations
+	onLookup    func(Lookup)
+	// name of the translation, for errors
+	name string
 }
 
 func New(ctx context.Context, conn repository.DBTX, env string) *Bible {
@@ -109,7 +134,17 @@ func (app *Bible) init() *Bible {
 		log.Fatal("initialization failed: ", err)
 	}
 
-	defBooksNumbers := []repository.Book{
+	app.books = books
+
+	return app
+}
+
+// books of the MyBible numbering with English names. They are used
+// when the module does not know the name and to format references
+var defBooks = defaultBooks()
+
+func defaultBooks() []repository.Book {
+	return []repository.Book{
 		{
 			BookNumber: 10,
 
@@ -622,11 +657,6 @@ func (app *Bible) init() *Bible {
 			BookColor:  "#c0c0c0",
 		},
 	}
-
-	app.books = books
-	app.defBookNumbers = defBooksNumbers
-
-	return app
 }
 
 func (app *Bible) SetEnvironment(s string) *Bible {
@@ -649,10 +679,11 @@ func (app *Bible) GetChapters(n int) ([]Verse, error) {
 
 	for _, v := range verses {
 		result = append(result, Verse{
-			Book:    app.getBookName(v.BookNumber),
-			Chapter: int(v.Chapter),
-			Verse:   int(v.Verse),
-			Text:    v.Text,
+			Book:       app.getBookName(v.BookNumber),
+			BookNumber: int(v.BookNumber),
+			Chapter:    int(v.Chapter),
+			Verse:      int(v.Verse),
+			Text:       v.Text,
 		})
 
 	}
@@ -676,14 +707,43 @@ func (app *Bible) Search(s string) ([]Verse, error) {
 
 	for _, v := range verses {
 		result = append(result, Verse{
-			Book:    app.getBookName(v.BookNumber),
-			Chapter: int(v.Chapter),
-			Verse:   int(v.Verse),
-			Text:    v.Text,
+			Book:       app.getBookName(v.BookNumber),
+			BookNumber: int(v.BookNumber),
+			Chapter:    int(v.Chapter),
+			Verse:      int(v.Verse),
+			Text:       v.Text,
 		})
 
 	}
-	return result, nil
+	return app.canonOrder(result), nil
+}
+
+// returns metadata of the module: description, language, etc.
+func (app *Bible) Info() (map[string]string, error) {
+	rows, err := app.db.GetInfo(app.ctx)
+	if err != nil {
+		return map[string]string{}, err
+	}
+
+	var info = make(map[string]string, len(rows))
+
+	for _, row := range rows {
+		info[row.Name] = row.Value
+	}
+
+	return info, nil
+}
+
+// returns number of chapters the book has in the module
+func (app *Bible) ChapterCount(book int) (int, error) {
+	n, err := app.db.GetChapterCount(app.ctx, float64(book))
+	return int(n), err
+}
+
+// resolves book name, abbreviation or alias to the book number
+func (app *Bible) LookupBook(name string) (int, bool) {
+	n := int(app.getBookNumber(name))
+	return n, n != 0
 }
 
 func (app *Bible) getBookName(num float64) string {
@@ -696,31 +756,42 @@ func (app *Bible) getBookName(num float64) string {
 	return fmt.Sprintf("undefined(%v)", num)
 }
 
+// module names are tried first, then names added with AddAliasTable
+// and then the shipped tables
 func (app *Bible) getBookNumber(s string) float64 {
+	s = normalizeOrdinals(s)
+
+	if name, ok := app.aliases[strings.ToLower(s)]; ok {
+		s = name
+	}
+
 	for _, book := range app.books {
-		if strings.ToLower(s) == strings.ToLower(book.LongName) {
+		if aliasKey(s) == aliasKey(book.LongName) {
 			return float64(book.BookNumber)
 		}
 
-		if strings.ToLower(s) == strings.ToLower(book.ShortName) {
+		if aliasKey(s) == aliasKey(book.ShortName) {
 			return float64(book.BookNumber)
 		}
 	}
 
-	for _, book := range app.defBookNumbers {
-		if strings.ToLower(s) == strings.ToLower(book.LongName) {
-			return float64(book.BookNumber)
-		}
+	if number, ok := app.bookAliases.lookup(s); ok {
+		return float64(number)
+	}
 
-		if strings.ToLower(s) == strings.ToLower(book.ShortName) {
-			return float64(book.BookNumber)
-		}
+	if number, ok := defAliases.lookup(s); ok {
+		return float64(number)
 	}
 
 	return 0
 }
 
 func (app *Bible) GetVersesRange(r RangeRequest) ([]Verse, error) {
+	// range of chapters ends with the last verse
+	if r.End.verse == 0 {
+		r.End.verse = MAX_VERSE
+	}
+
 	if r.Simple() {
 		resp, err := app.requestRange(r.Start.book, r.Start.chapter, r.Start.verse, r.End.verse)
 		return wrapVerses(r.Start.book, resp), err
@@ -752,8 +823,7 @@ func (app *Bible) GetChapter(book int, chapter int) ([]Verse, error) {
 		return []Verse{}, err
 	}
 
-	//no book name for now
-	return wrapVerses("", v), nil
+	return wrapVerses(app.getBookName(float64(book)), v), nil
 }
 
 func (app *Bible) requestRange(name string, chapter, from, to float64) ([]repository.Verse, error) {
@@ -797,6 +867,12 @@ func (app *Bible) SetRender(r Renderer) *Bible {
 	return app
 }
 
+// words that renderer should highlight in the text
+func (app *Bible) SetHighlights(ss []string) *Bible {
+	app.render.SetHighlights(ss)
+	return app
+}
+
 func (app *Bible) SetQuery(s string) *Bible {
 	app.query = s
 	return app
@@ -817,58 +893,327 @@ func (app *Bible) SetWriter(w io.Writer) *Bible {
 	return app
 }
 
+// when set section headings from the module are attached to verses
+func (app *Bible) SetHeadings(b bool) *Bible {
+	app.headings = b
+	return app
+}
+
+// adds extra names for books. Keys are aliases and values are book
+// names or abbreviations the module understands
+func (app *Bible) SetAliases(aliases map[string]string) *Bible {
+	app.aliases = make(map[string]string, len(aliases))
+
+	for alias, name := range aliases {
+		app.aliases[strings.ToLower(alias)] = name
+	}
+
+	return app
+}
+
+// will look up headings for every chapter in verses and attach
+// them to the verses they belong to
+func (app *Bible) attachHeadings(verses []Verse) error {
+	if len(verses) < 1 {
+		return nil
+	}
+
+	for _, c := range splitIntoChapters(verses) {
+		if c[0].BookNumber == 0 || c[0].Chapter == 0 {
+			continue
+		}
+
+		stories, err := app.db.GetStories(app.ctx, repository.GetStoriesParams{
+			BookNumber: float64(c[0].BookNumber),
+			Chapter:    float64(c[0].Chapter),
+		})
+		if err != nil {
+			return err
+		}
+
+		for _, story := range stories {
+			for i := range c {
+				if c[i].Verse != int(story.Verse) {
+					continue
+				}
+
+				if c[i].Heading != "" {
+					c[i].Heading += "\n"
+				}
+				c[i].Heading += story.Title
+			}
+		}
+	}
+
+	return nil
+}
+
+// when set a book name alone reads the whole book. Otherwise first
+// verses of its chapters are listed
+func (app *Bible) SetWholeBooks(b bool) *Bible {
+	app.wholeBooks = b
+	return app
+}
+
+// names the translation in errors, e.g. `John 99:1 not found in ESV`
+func (app *Bible) SetName(name string) *Bible {
+	app.name = name
+	return app
+}
+
+// NotFoundError is returned for references that were read fine but
+// have no verses in the translation
+type NotFoundError struct {
+	Reference   string
+	Translation string
+}
+
+func (e *NotFoundError) Error() string {
+	if e.Translation == "" {
+		return fmt.Sprintf("`%s` not found", e.Reference)
+	}
+
+	return fmt.Sprintf("`%s` not found in %s", e.Reference, e.Translation)
+}
+
+func (app *Bible) notFound(passages []Passage) *NotFoundError {
+	lookup := Lookup{Passages: NewPassageSet(passages...)}
+
+	return &NotFoundError{Reference: lookup.Reference(), Translation: app.name}
+}
+
+// limits number of verses returned by Execute, context verses are not
+// counted. 0 means no limit
+func (app *Bible) SetLimit(n int) *Bible {
+	app.limit = n
+	return app
+}
+
+// number of verses to show before and after every verse Execute
+// finds. Context goes over chapter and book boundaries
+func (app *Bible) SetContextVerses(before, after int) *Bible {
+	app.before = before
+	app.after = after
+	return app
+}
+
+// AddContext extends every verse with the context set by
+// SetContextVerses. Verses that were not in the list are marked as
+// Context. Overlapping windows are merged, so every verse is there once
+func (app *Bible) AddContext(verses []Verse) ([]Verse, error) {
+	if app.before < 1 && app.after < 1 {
+		return verses, nil
+	}
+
+	var result []Verse
+	var seen = map[[3]int]int{}
+
+	for _, v := range verses {
+		// list of books has no context
+		if v.Chapter < 1 {
+			return verses, nil
+		}
+
+		from, err := app.reach(v, -app.before)
+		if err != nil {
+			return verses, err
+		}
+
+		to, err := app.reach(v, app.after)
+		if err != nil {
+			return verses, err
+		}
+
+		window, err := app.Between(from, to)
+		if err != nil {
+			return verses, err
+		}
+
+		for _, w := range window {
+			key := [3]int{w.BookNumber, w.Chapter, w.Verse}
+			requested := key == [3]int{v.BookNumber, v.Chapter, v.Verse}
+
+			if i, ok := seen[key]; ok {
+				if requested {
+					result[i].Context = false
+				}
+				continue
+			}
+
+			w.Context = !requested
+
+			seen[key] = len(result)
+			result = append(result, w)
+		}
+	}
+
+	return result, nil
+}
+
+// will move n verses away from v. When the bible ends sooner returns
+// a position before the first or after the last verse
+func (app *Bible) reach(v Verse, n int) (Verse, error) {
+	if n == 0 {
+		return v, nil
+	}
+
+	r, err := app.Offset(v, n)
+
+	switch {
+	case errors.Is(err, ErrStartOfBible):
+		return Verse{}, nil
+	case errors.Is(err, ErrEndOfBible):
+		return Verse{BookNumber: math.MaxInt32}, nil
+	}
+
+	return r, err
+}
+
 func (app *Bible) Execute() ([]Verse, error) {
+	verses, passages, err := app.execute()
+
+	if app.limit > 0 && len(verses) > app.limit {
+		verses = verses[:app.limit]
+	}
+
+	if err == nil && app.onLookup != nil && len(passages) > 0 && len(verses) > 0 {
+		app.onLookup(newLookup(app.query, passages, verses))
+	}
+
+	if err == nil {
+		verses, err = app.AddContext(verses)
+	}
+
+	if err == nil && app.headings {
+		err = app.attachHeadings(verses)
+	}
+
+	if err == nil && app.annotations != nil {
+		err = app.attachAnnotations(verses)
+	}
+
+	return verses, err
+}
+
+// returns verses of the query and passages it was read as. Searches
+// have no passages
+func (app *Bible) execute() ([]Verse, []Passage, error) {
 	request, err := Parse(app.query)
+
+	// text that does not start with a book is words to search for
+	var parseErr *ParseError
+	if errors.As(err, &parseErr) && !app.isBook(parseErr.Book) {
+		verses, err := app.searchQuery()
+		return verses, nil, err
+	}
+
 	if err != nil {
-		return []Verse{}, err
+		return []Verse{}, nil, err
 	}
 
 	var verses []Verse
+	var passages []Passage
+
 	switch r := request.(type) {
 	case EmptyRequest:
 		// I want to return list of books
-		books, err := app.GetBooks()
+		books, err := app.Books()
 
 		if err != nil {
-			return []Verse{}, err
+			return []Verse{}, nil, err
 		}
 
-		return wrapBooks(books), nil
+		return wrapBooks(books), nil, nil
 	case ConcreteRequest:
-		bookNumber := app.getBookNumber(r.ref.book)
-		if bookNumber == 0 {
+		bookNumber, err := app.nameOnlyBook(r.ref.book)
+		if errors.Is(err, errNotReference) {
 			break
 		}
-		return app.GetChapters(int(bookNumber))
-	case RangeRequest:
-		return app.GetVersesRange(r)
-	case CollectionRequest:
-		return app.GetVersesCollection(r)
-	case MixedRequest:
-		return []Verse{}, errors.New("MIXED REQUESTS ARE NOT IMPLEMENTED")
-	}
 
-	if len(verses) < 1 {
-		verses, err = app.Search(app.query)
+		if err != nil {
+			return []Verse{}, nil, err
+		}
+
+		passages = []Passage{NewPassage(Reference{BookNumber: bookNumber})}
+
+		if app.wholeBooks {
+			verses, err := app.GetPassages(NewPassageSet(passages...))
+			if err == nil && len(verses) < 1 {
+				err = app.notFound(passages)
+			}
+			return verses, passages, err
+		}
+
+		verses, err := app.GetChapters(bookNumber)
+		if err == nil && len(verses) < 1 {
+			err = app.notFound(passages)
+		}
+		return verses, nil, err
+	case RangeRequest, CollectionRequest, MixedRequest:
+		// unknown name without numbers means the query is not a reference
+		passages, err = app.Passages(r)
+		if errors.Is(err, errNotReference) {
+			break
+		}
 
 		if err != nil {
-			return []Verse{}, err
+			return []Verse{}, nil, err
 		}
 
-		app.render.SetHighlights(strings.Split(app.query, " "))
+		verses, err = app.GetPassages(NewPassageSet(passages...))
+		if err != nil {
+			return []Verse{}, nil, err
+		}
 
-		return verses, nil
+		verses = app.canonPassages(verses, passages)
+		if len(verses) < 1 {
+			return []Verse{}, nil, app.notFound(passages)
+		}
 	}
 
+	// only words that are not a reference are searched for
 	if len(verses) < 1 {
-		return verses, errors.New("nothing found!")
+		verses, err := app.searchQuery()
+		return verses, nil, err
+	}
+
+	return verses, passages, nil
+}
+
+// will search for the words of the query and highlight them
+func (app *Bible) searchQuery() ([]Verse, error) {
+	verses, err := app.Search(app.query)
+	if err != nil {
+		return []Verse{}, err
+	}
+
+	app.render.SetHighlights(strings.Split(app.query, " "))
+
+	return verses, nil
+}
+
+// reports whether the name is a book, misspelled names count
+func (app *Bible) isBook(name string) bool {
+	if name == "" {
+		return false
 	}
 
-	return []Verse{}, err
+	_, err := app.findBook(name)
+	return err == nil
+}
 
+// renders verses with the renderer of the app into its writer
+func (app *Bible) Render(verses []Verse) error {
+	return app.render.Render(app.writer, verses)
 }
 
+// prints verses of the query. Whole books and ranges over books are
+// streamed, so they start printing right away
 func (app *Bible) Run() error {
+	if passages, ok := app.streamed(); ok {
+		return app.RenderSeq(app.readSeq(passages))
+	}
+
 	verses, err := app.Execute()
 	if err != nil {
 		return err
@@ -1,7 +1,44 @@
 package bible
 
-import "testing"
+import (
+	"errors"
+	"testing"
+)
 
 func TestSomething(t *testing.T) {
 
 }
+
+func TestNotFound(t *testing.T) {
+	app := testModule(t).SetName("TEST")
+
+	tests := []string{
+		"John 5",
+		"John 99:1",
+		"John 20:99",
+		"Acts 1:4-9",
+		"John 20:99, Acts 2:1",
+	}
+
+	expectedResults := []string{
+		"`John 5` not found in TEST",
+		"`John 99:1` not found in TEST",
+		"`John 20:99` not found in TEST",
+		"`Acts 1:4-9` not found in TEST",
+		"`John 20:99, Acts 2:1` not found in TEST",
+	}
+
+	for i, test := range tests {
+		_, err := app.SetQuery(test).Execute()
+
+		var notFound *NotFoundError
+		if !errors.As(err, &notFound) || err.Error() != expectedResults[i] {
+			t.Fatalf("TEST[%d] failed: expected %q, got %v", i, expectedResults[i], err)
+		}
+	}
+
+	// words are still searched for
+	if verses, err := app.SetQuery("text").Execute(); err != nil || len(verses) < 1 {
+		t.Fatalf("TEST[%d] failed: expected search results, got %v", len(tests), err)
+	}
+}
@@ -0,0 +1,206 @@
+package bible
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/ButbkaDrug/bible/internal/repository"
+)
+
+const (
+	CANON_PROTESTANT = "protestant"
+	CANON_CATHOLIC   = "catholic"
+	CANON_ORTHODOX   = "orthodox"
+)
+
+// Canon is a set of books in the order a tradition prints them. It
+// decides which books are listed and searched. Books outside of it can
+// still be read by their name.
+type Canon struct {
+	Name  string
+	Books []int
+}
+
+// 39 books of the Old Testament in the order of English Bibles
+var protestantOT = []int{
+	10, 20, 30, 40, 50, 60, 70, 80, 90, 100, 110, 120, 130, 140, 150, 160,
+	190, 220, 230, 240, 250, 260, 290, 300, 310, 330, 340,
+	350, 360, 370, 380, 390, 400, 410, 420, 430, 440, 450, 460,
+}
+
+// 27 books of the New Testament in the order of Western Bibles
+var newTestament = []int{
+	470, 480, 490, 500, 510, 520, 530, 540, 550, 560, 570, 580, 590,
+	600, 610, 620, 630, 640, 650, 660, 670, 680, 690, 700, 710, 720, 730,
+}
+
+// Protestant has 66 books
+var Protestant = Canon{
+	Name:  CANON_PROTESTANT,
+	Books: slices.Concat(protestantOT, newTestament),
+}
+
+// Catholic has 73 books. Greek parts of Esther and Daniel and the
+// Letter of Jeremiah are kept next to the books they belong to
+var Catholic = Canon{
+	Name: CANON_CATHOLIC,
+	Books: slices.Concat([]int{
+		10, 20, 30, 40, 50, 60, 70, 80, 90, 100, 110, 120, 130, 140, 150, 160,
+		170, 180, 190, 192, 462, 464,
+		220, 230, 240, 250, 260, 270, 280,
+		290, 300, 310, 320, 315, 330, 340, 305, 323, 325, 345,
+		350, 360, 370, 380, 390, 400, 410, 420, 430, 440, 450, 460,
+	}, newTestament),
+}
+
+// Orthodox follows the Slavonic Bible: books of Ezra and Maccabees that
+// others do not have, Catholic Epistles right after Acts
+var Orthodox = Canon{
+	Name: CANON_ORTHODOX,
+	Books: []int{
+		10, 20, 30, 40, 50, 60, 70, 80, 90, 100, 110, 120, 130, 140, 790,
+		150, 160, 165, 170, 180, 190, 192,
+		220, 230, 232, 240, 250, 260, 270, 280,
+		290, 300, 310, 315, 320, 330, 340, 305, 323, 325, 345,
+		350, 360, 370, 380, 390, 400, 410, 420, 430, 440, 450, 460,
+		462, 464, 466, 468,
+		470, 480, 490, 500, 510,
+		660, 670, 680, 690, 700, 710, 720,
+		520, 530, 540, 550, 560, 570, 580, 590, 600, 610, 620, 630, 640, 650,
+		730,
+	},
+}
+
+var defCanons = []Canon{Protestant, Catholic, Orthodox}
+
+// LookupCanon returns one of the shipped canons by name
+func LookupCanon(name string) (Canon, bool) {
+	for _, c := range defCanons {
+		if strings.EqualFold(c.Name, name) {
+			return c, true
+		}
+	}
+
+	return Canon{}, false
+}
+
+// NewCanon builds a canon from names of books and canons, so
+// ["protestant", "Tobit"] is the Protestant canon with Tobit at the end.
+// Books are looked up the same way references are
+func (app *Bible) NewCanon(name string, entries []string) (Canon, error) {
+	var canon = Canon{Name: name}
+
+	for _, entry := range entries {
+		if c, ok := LookupCanon(entry); ok {
+			canon.Books = append(canon.Books, c.Books...)
+			continue
+		}
+
+		number, ok := app.LookupBook(entry)
+		if !ok {
+			return Canon{}, fmt.Errorf("canon %s: unknown book `%s`", name, entry)
+		}
+
+		canon.Books = append(canon.Books, number)
+	}
+
+	// first place of a book wins
+	var books []int
+	for _, n := range canon.Books {
+		if !slices.Contains(books, n) {
+			books = append(books, n)
+		}
+	}
+	canon.Books = books
+
+	return canon, nil
+}
+
+// Empty canon has every book
+func (c Canon) Empty() bool {
+	return len(c.Books) == 0
+}
+
+// Has reports whether the book is in the canon
+func (c Canon) Has(book int) bool {
+	return c.Empty() || slices.Contains(c.Books, book)
+}
+
+// position of the book in the canon. Books of an empty canon are kept
+// in the order of their numbers
+func (c Canon) position(book int) int {
+	if c.Empty() {
+		return book
+	}
+
+	return slices.Index(c.Books, book)
+}
+
+// SetCanon limits the book list and search to the books of the canon and
+// puts them in its order. Zero Canon brings back every book of the module
+func (app *Bible) SetCanon(c Canon) *Bible {
+	app.canon = c
+	return app
+}
+
+// CanonBooks returns books of the module that are in the canon, in its
+// order
+func (app *Bible) CanonBooks() ([]repository.Book, error) {
+	books, err := app.GetBooks()
+	if err != nil {
+		return nil, err
+	}
+
+	if app.canon.Empty() {
+		return books, nil
+	}
+
+	books = slices.DeleteFunc(books, func(b repository.Book) bool {
+		return !app.canon.Has(int(b.BookNumber))
+	})
+
+	slices.SortStableFunc(books, func(a, b repository.Book) int {
+		return cmp.Compare(app.canon.position(int(a.BookNumber)), app.canon.position(int(b.BookNumber)))
+	})
+
+	return books, nil
+}
+
+// drops verses of books that are not in the canon and puts the rest in
+// its order, verses of a book keep their order
+func (app *Bible) canonOrder(verses []Verse) []Verse {
+	if app.canon.Empty() {
+		return verses
+	}
+
+	verses = slices.DeleteFunc(verses, func(v Verse) bool {
+		return !app.canon.Has(v.BookNumber)
+	})
+
+	slices.SortStableFunc(verses, func(a, b Verse) int {
+		return cmp.Compare(app.canon.position(a.BookNumber), app.canon.position(b.BookNumber))
+	})
+
+	return verses
+}
+
+// books a passage goes through on the way from one book to another are
+// skipped when they are not in the canon. Books the passages start or
+// end in were asked for by name, so they stay
+func (app *Bible) canonPassages(verses []Verse, passages []Passage) []Verse {
+	if app.canon.Empty() {
+		return verses
+	}
+
+	var named = make(map[int]bool)
+	for _, p := range passages {
+		named[p.Start.BookNumber] = true
+		named[p.End.BookNumber] = true
+	}
+
+	return slices.DeleteFunc(verses, func(v Verse) bool {
+		return !named[v.BookNumber] && !app.canon.Has(v.BookNumber)
+	})
+}
@@ -0,0 +1,107 @@
+package bible
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCanonSizes(t *testing.T) {
+	tests := []Canon{Protestant, Catholic, Orthodox}
+
+	expectedResults := []int{66, 73, 78}
+
+	for i, test := range tests {
+		var books = make(map[int]bool)
+		for _, n := range test.Books {
+			if books[n] {
+				t.Fatalf("TEST[%d] failed: %s has book %d twice", i, test.Name, n)
+			}
+			books[n] = true
+		}
+
+		// parts of Esther, Jeremiah and Daniel are not books of their own
+		for _, n := range []int{192, 305, 315, 323, 325, 345} {
+			delete(books, n)
+		}
+
+		if len(books) != expectedResults[i] {
+			t.Fatalf("TEST[%d] failed: %s expected %d books got %d", i, test.Name, expectedResults[i], len(books))
+		}
+	}
+}
+
+func TestNewCanon(t *testing.T) {
+	app := testModule(t)
+
+	tests := [][]string{
+		{"Acts", "John"},
+		{"protestant", "Tobit"},
+		{"Jn", "Catholic"},
+	}
+
+	expectedResults := []struct {
+		size, first, last int
+	}{
+		{2, 510, 500},
+		{67, 10, 170},
+		{79, 500, 730},
+	}
+
+	for i, test := range tests {
+		canon, err := app.NewCanon("test", test)
+		if err != nil {
+			t.Fatalf("TEST[%d] failed: %s", i, err)
+		}
+
+		expected := expectedResults[i]
+		books := canon.Books
+
+		if len(books) != expected.size || books[0] != expected.first || books[len(books)-1] != expected.last {
+			t.Fatalf("TEST[%d] failed: expected %v got %v", i, expected, books)
+		}
+	}
+
+	if _, err := app.NewCanon("test", []string{"Hezekiah"}); err == nil {
+		t.Fatalf("expected an error for unknown book")
+	}
+}
+
+func TestCanonExecute(t *testing.T) {
+	app := testModule(t).SetCanon(Canon{Books: []int{510, 500}})
+
+	tests := []string{
+		"",
+		"text",
+	}
+
+	expectedResults := []string{
+		"Acts John",
+		"Acts Acts Acts John John John John John",
+	}
+
+	for i, test := range tests {
+		verses, err := app.SetQuery(test).Execute()
+		if err != nil {
+			t.Fatalf("TEST[%d] failed: %s", i, err)
+		}
+
+		var books []string
+		for _, v := range verses {
+			books = append(books, v.Book)
+		}
+
+		if result := strings.Join(books, " "); result != expectedResults[i] {
+			t.Fatalf("TEST[%d] failed: expected %q got %q", i, expectedResults[i], result)
+		}
+	}
+
+	app.SetCanon(Canon{Books: []int{500}})
+
+	if verses, _ := app.SetQuery("text").Execute(); len(verses) != 5 {
+		t.Fatalf("expected only verses of John got %d", len(verses))
+	}
+
+	if verses, _ := app.SetQuery("Acts 1:1").Execute(); len(verses) != 1 {
+		t.Fatalf("expected books outside of the canon to be read by name")
+	}
+}
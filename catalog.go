@@ -0,0 +1,223 @@
+package bible
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/ButbkaDrug/bible/internal/repository"
+)
+
+type Testament int
+
+const (
+	OLD_TESTAMENT Testament = iota + 1
+	NEW_TESTAMENT
+)
+
+var testamentNames = map[Testament]string{
+	OLD_TESTAMENT: "Old",
+	NEW_TESTAMENT: "New",
+}
+
+func (t Testament) String() string {
+	return testamentNames[t]
+}
+
+func (t Testament) MarshalText() ([]byte, error) {
+	return []byte(strings.ToLower(t.String())), nil
+}
+
+// ParseTestament reads `old`, `ot`, `new` or `nt` in any case
+func ParseTestament(s string) (Testament, bool) {
+	switch strings.ToLower(s) {
+	case "old", "ot":
+		return OLD_TESTAMENT, true
+	case "new", "nt":
+		return NEW_TESTAMENT, true
+	}
+
+	return 0, false
+}
+
+type Genre int
+
+const (
+	LAW Genre = iota + 1
+	HISTORY
+	WISDOM
+	PROPHETS
+	GOSPELS
+	EPISTLES
+	APOCALYPTIC
+)
+
+var genreNames = map[Genre]string{
+	LAW:         "Law",
+	HISTORY:     "History",
+	WISDOM:      "Wisdom",
+	PROPHETS:    "Prophets",
+	GOSPELS:     "Gospels",
+	EPISTLES:    "Epistles",
+	APOCALYPTIC: "Apocalyptic",
+}
+
+func (g Genre) String() string {
+	return genreNames[g]
+}
+
+func (g Genre) MarshalText() ([]byte, error) {
+	return []byte(strings.ToLower(g.String())), nil
+}
+
+// ParseGenre reads a genre name in any case, `gospel` and `prophet`
+// work too
+func ParseGenre(s string) (Genre, bool) {
+	for g, name := range genreNames {
+		if strings.EqualFold(name, s) || strings.EqualFold(strings.TrimSuffix(name, "s"), s) {
+			return g, true
+		}
+	}
+
+	return 0, false
+}
+
+// Book is an entry of the catalog of the module
+type Book struct {
+	Number    int       `json:"book_number"`
+	ShortName string    `json:"short_name"`
+	LongName  string    `json:"long_name"`
+	Aliases   []string  `json:"aliases,omitempty"`
+	Testament Testament `json:"testament,omitempty"`
+	// zero for books the MyBible numbering does not know
+	Genre    Genre  `json:"genre,omitempty"`
+	Color    string `json:"color,omitempty"`
+	Chapters int    `json:"chapters"`
+	Verses   int    `json:"verses"`
+}
+
+func (b Book) String() string {
+	return b.LongName
+}
+
+// testament and genre of a book by its MyBible number
+func bookKind(number int) (Testament, Genre) {
+	switch {
+	case number <= 50:
+		return OLD_TESTAMENT, LAW
+	case number <= 192, number >= 462 && number <= 467:
+		return OLD_TESTAMENT, HISTORY
+	case number <= 280, number == 790:
+		return OLD_TESTAMENT, WISDOM
+	case number <= 460:
+		return OLD_TESTAMENT, PROPHETS
+	case number == 468:
+		return OLD_TESTAMENT, APOCALYPTIC
+	case number <= 500:
+		return NEW_TESTAMENT, GOSPELS
+	case number == 510:
+		return NEW_TESTAMENT, HISTORY
+	case number <= 720:
+		return NEW_TESTAMENT, EPISTLES
+	case number == 730:
+		return NEW_TESTAMENT, APOCALYPTIC
+	}
+
+	return 0, 0
+}
+
+// Books returns the catalog of the module: books of the canon in its
+// order with their names, kind and size
+func (app *Bible) Books() ([]Book, error) {
+	books, err := app.CanonBooks()
+	if err != nil {
+		return nil, err
+	}
+
+	stats, err := app.db.GetBookStats(app.ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var result = make([]Book, len(books))
+
+	for i, b := range books {
+		number := int(b.BookNumber)
+		testament, genre := bookKind(number)
+
+		result[i] = Book{
+			Number:    number,
+			ShortName: b.ShortName,
+			LongName:  b.LongName,
+			Aliases:   app.bookAliasesOf(b),
+			Testament: testament,
+			Genre:     genre,
+			Color:     b.BookColor,
+		}
+
+		for _, s := range stats {
+			if s.BookNumber == b.BookNumber {
+				result[i].Chapters = int(s.Chapters)
+				result[i].Verses = int(s.Verses)
+			}
+		}
+	}
+
+	return result, nil
+}
+
+// ParseBookFilter returns a filter for Books by testament or genre
+// name, e.g. `nt` or `gospels`
+func ParseBookFilter(s string) (func(Book) bool, error) {
+	if t, ok := ParseTestament(s); ok {
+		return func(b Book) bool { return b.Testament == t }, nil
+	}
+
+	if g, ok := ParseGenre(s); ok {
+		return func(b Book) bool { return b.Genre == g }, nil
+	}
+
+	var genres []string
+	for g := LAW; g <= APOCALYPTIC; g++ {
+		genres = append(genres, strings.ToLower(g.String()))
+	}
+
+	return nil, fmt.Errorf("unknown testament or genre `%s`, expected old, new, %s", s, strings.Join(genres, ", "))
+}
+
+// other names the book can be found by, the ones set by the user first.
+// Names another book took first are not listed
+func (app *Bible) bookAliasesOf(b repository.Book) []string {
+	number := int(b.BookNumber)
+	seen := []string{aliasKey(b.LongName), aliasKey(b.ShortName)}
+
+	var result []string
+	add := func(names ...string) {
+		for _, name := range names {
+			key := aliasKey(name)
+			if key == "" || slices.Contains(seen, key) || int(app.getBookNumber(name)) != number {
+				continue
+			}
+
+			seen = append(seen, key)
+			result = append(result, name)
+		}
+	}
+
+	var aliases []string
+	for alias := range app.aliases {
+		aliases = append(aliases, alias)
+	}
+	slices.Sort(aliases)
+	add(aliases...)
+
+	for _, t := range app.aliasTables {
+		add(t[number]...)
+	}
+
+	for _, t := range []AliasTable{booksTable(defBooks), SBLAbbreviations, EnglishNames, RussianNames, UkrainianNames} {
+		add(t[number]...)
+	}
+
+	return result
+}
@@ -0,0 +1,93 @@
+package bible
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestBooks(t *testing.T) {
+	app := testModule(t).
+		SetAliases(map[string]string{"ev": "John"}).
+		AddAliasTable(AliasTable{500: {"Йн"}, 510: {"Jn"}})
+
+	books, err := app.Books()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []string{
+		fmt.Sprintf("%d %s %s %s %d %d", books[0].Number, books[0].LongName, books[0].Testament, books[0].Genre, books[0].Chapters, books[0].Verses),
+		fmt.Sprintf("%d %s %s %s %d %d", books[1].Number, books[1].LongName, books[1].Testament, books[1].Genre, books[1].Chapters, books[1].Verses),
+		fmt.Sprint(books[0].Aliases[:3]),
+	}
+
+	expectedResults := []string{
+		"500 John New Gospels 2 5",
+		"510 Acts New History 1 3",
+		// Jn is the short name of John in the module, so Acts does not get it
+		"[ev Йн Jhn]",
+	}
+
+	for i, test := range tests {
+		if test != expectedResults[i] {
+			t.Fatalf("TEST[%d] failed: expected %q got %q", i, expectedResults[i], test)
+		}
+	}
+
+	books, err = app.SetCanon(Canon{Books: []int{510}}).Books()
+	if err != nil || len(books) != 1 || books[0].Number != 510 {
+		t.Fatalf("expected only books of the canon got %v %v", books, err)
+	}
+}
+
+func TestParseBookFilter(t *testing.T) {
+	books := []Book{
+		{Number: 10, Testament: OLD_TESTAMENT, Genre: LAW},
+		{Number: 470, Testament: NEW_TESTAMENT, Genre: GOSPELS},
+		{Number: 730, Testament: NEW_TESTAMENT, Genre: APOCALYPTIC},
+	}
+
+	tests := []string{"OT", "new", "gospel", "Apocalyptic", "law"}
+
+	expectedResults := []string{"[10]", "[470 730]", "[470]", "[730]", "[10]"}
+
+	for i, test := range tests {
+		f, err := ParseBookFilter(test)
+		if err != nil {
+			t.Fatalf("TEST[%d] failed: %s", i, err)
+		}
+
+		var result []int
+		for _, b := range books {
+			if f(b) {
+				result = append(result, b.Number)
+			}
+		}
+
+		if fmt.Sprint(result) != expectedResults[i] {
+			t.Fatalf("TEST[%d] failed: expected %s got %v", i, expectedResults[i], result)
+		}
+	}
+
+	if _, err := ParseBookFilter("poetry"); err == nil {
+		t.Fatalf("expected an error for unknown genre")
+	}
+}
+
+func TestBookKind(t *testing.T) {
+	tests := []int{10, 170, 230, 340, 462, 468, 480, 510, 640, 730, 790, 900}
+
+	expectedResults := []string{
+		"Old Law", "Old History", "Old Wisdom", "Old Prophets", "Old History",
+		"Old Apocalyptic", "New Gospels", "New History", "New Epistles",
+		"New Apocalyptic", "Old Wisdom", " ",
+	}
+
+	for i, test := range tests {
+		testament, genre := bookKind(test)
+
+		if result := fmt.Sprintf("%s %s", testament, genre); result != expectedResults[i] {
+			t.Fatalf("TEST[%d] failed: %d expected %q got %q", i, test, expectedResults[i], result)
+		}
+	}
+}
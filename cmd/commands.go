@@ -0,0 +1,300 @@
+package main
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/ButbkaDrug/bible"
+	"github.com/ButbkaDrug/bible/internal/repository"
+)
+
+type command struct {
+	name    string
+	args    string
+	summary string
+	run     func(s *session, args []string) error
+}
+
+var commands []command
+
+func init() {
+	commands = []command{
+		{"read", "[reference]", "read verses, e.g. `john 3:16`. This is the default command", runRead},
+		{"search", "<words>", "search for verses containing the words", runSearch},
+		{"books", "[testament|genre]", "list books of the translation, e.g. `books nt` or `books wisdom`", runBooks},
+		{"info", "", "show information about the translation", runInfo},
+		{"modules", "", "list translations found in the module directories", runModules},
+		{"random", "[testament|genre|references]", "print a random verse, e.g. `random nt` or `random ps 23, rom 8`", runRandom},
+		{"votd", "[date] [testament|genre|references]", "print the verse of the day, the same for everyone", runVotd},
+		{"plan", "[command] [plan]", "follow a reading plan, see `bible plan list`", runPlan},
+		{"note", "[add|list|rm] [reference] [text]", "keep notes on passages, e.g. `note add john 3:16 \"text\"`", runNote},
+		{"notes", "[reference]", "list your notes, of the passage when it is given", noteList},
+		{"tag", "[add|list|rm] [reference] [tag]", "tag passages, e.g. `tag add rom 8:28 hope`", runTag},
+		{"tags", "[tag]", "list your tags or passages with the tag", tagList},
+		{"highlight", "[add|list|rm] [reference] [style]", "highlight passages, e.g. `highlight add ps 23 green`", runHighlight},
+		{"bookmark", "[add|list|rm] [reference]", "bookmark passages", runBookmark},
+		{"bookmarks", "", "list your bookmarks", bookmarkList},
+		{"history", "[list|run|top|clear] [id]", "list recent lookups, `history 3` reads one again", runHistory},
+		{"last", "", "read the last lookup again, e.g. `last -t NIV -f json`", runLast},
+		{"filter", "[annotate|expand]", "add verse text to references found in stdin", runFilter},
+		{"tui", "[reference]", "read in a full screen reader", runTUI},
+		{"config", "", "show effective configuration", runConfig},
+		{"completion", "<bash|zsh|fish>", "print shell completion script", runCompletion},
+		{"help", "[command]", "show help", runHelp},
+		{"__complete", "<words>", "used by completion scripts", runComplete},
+	}
+}
+
+func findCommand(name string) (command, bool) {
+	for _, c := range commands {
+		if c.name == name {
+			return c, true
+		}
+	}
+
+	return command{}, false
+}
+
+func printUsage() {
+	w := os.Stderr
+
+	fmt.Fprintf(w, "Usage: bible [command] [flags] [arguments]\n\nCommands:\n")
+
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	for _, c := range commands {
+		if strings.HasPrefix(c.name, "__") {
+			continue
+		}
+		fmt.Fprintf(tw, "  %s %s\t%s\n", c.name, c.args, c.summary)
+	}
+	tw.Flush()
+
+	fmt.Fprintf(w, "\nRun `bible help <command>` to see the flags.\n")
+}
+
+func printCommandUsage(fs *flag.FlagSet, c command) {
+	w := fs.Output()
+
+	fmt.Fprintf(w, "Usage: bible %s [flags] %s\n\n%s\n\nFlags:\n", c.name, c.args, c.summary)
+	fs.PrintDefaults()
+}
+
+func runRead(s *session, args []string) error {
+	app, err := s.bible()
+	if err != nil {
+		return err
+	}
+
+	return app.SetQuery(strings.Join(args, " ")).Run()
+}
+
+func runSearch(s *session, args []string) error {
+	if len(args) < 1 {
+		return errors.New("nothing to search for")
+	}
+
+	app, err := s.bible()
+	if err != nil {
+		return err
+	}
+
+	query := strings.Join(args, " ")
+
+	verses, err := app.Search(query)
+	if err != nil {
+		return err
+	}
+
+	if len(verses) < 1 {
+		return fmt.Errorf("nothing found for `%s`", query)
+	}
+
+	if s.limit > 0 && len(verses) > s.limit {
+		verses = verses[:s.limit]
+	}
+
+	verses, err = app.AddContext(verses)
+	if err != nil {
+		return err
+	}
+
+	return app.SetHighlights(args).Render(verses)
+}
+
+// lists books as a table, `bible books nt` or `bible books old wisdom`
+// keeps only books of the testaments and genres named
+func runBooks(s *session, args []string) error {
+	var filters []func(bible.Book) bool
+	for _, arg := range args {
+		f, err := bible.ParseBookFilter(arg)
+		if err != nil {
+			return err
+		}
+		filters = append(filters, f)
+	}
+
+	app, err := s.bible()
+	if err != nil {
+		return err
+	}
+
+	books, err := app.Books()
+	if err != nil {
+		return err
+	}
+
+	books = slices.DeleteFunc(books, func(b bible.Book) bool {
+		for _, f := range filters {
+			if !f(b) {
+				return true
+			}
+		}
+		return false
+	})
+
+	if len(books) < 1 {
+		return errors.New("no books found")
+	}
+
+	if s.cfg.Format == "json" {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(books)
+	}
+
+	tw := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintf(tw, "#\tShort\tName\tTestament\tGenre\tChapters\tVerses\n")
+	for _, b := range books {
+		fmt.Fprintf(tw, "%d\t%s\t%s\t%s\t%s\t%d\t%d\n",
+			b.Number, b.ShortName, b.LongName, b.Testament, b.Genre, b.Chapters, b.Verses)
+	}
+
+	return tw.Flush()
+}
+
+func runInfo(s *session, args []string) error {
+	app, err := s.bible()
+	if err != nil {
+		return err
+	}
+
+	info, err := app.Info()
+	if err != nil {
+		return err
+	}
+
+	var names []string
+	for name := range info {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	tw := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	for _, name := range names {
+		fmt.Fprintf(tw, "%s\t%s\n", name, info[name])
+	}
+
+	return tw.Flush()
+}
+
+type module struct {
+	name        string
+	path        string
+	description string
+}
+
+// finds module files in all the module directories. When a translation
+// is in several directories the first one wins, same as when reading
+func listModules(s *session) []module {
+	var modules []module
+	var seen = map[string]bool{}
+
+	for _, dir := range s.cfg.ModuleDirs {
+		paths, _ := filepath.Glob(filepath.Join(dir, "*."+s.cfg.Extension))
+
+		for _, path := range paths {
+			name := strings.TrimSuffix(filepath.Base(path), "."+s.cfg.Extension)
+
+			if seen[name] {
+				continue
+			}
+			seen[name] = true
+
+			modules = append(modules, module{name: name, path: path})
+		}
+	}
+
+	return modules
+}
+
+func moduleDescription(s *session, path string) string {
+	conn, err := sql.Open("sqlite", path)
+	if err != nil {
+		return ""
+	}
+	defer conn.Close()
+
+	info, err := repository.New(conn).GetInfo(s.ctx)
+	if err != nil {
+		return ""
+	}
+
+	for _, i := range info {
+		if i.Name == "description" {
+			return i.Value
+		}
+	}
+
+	return ""
+}
+
+func runModules(s *session, args []string) error {
+	modules := listModules(s)
+
+	if len(modules) < 1 {
+		return fmt.Errorf("no *.%s files in %s",
+			s.cfg.Extension,
+			strings.Join(s.cfg.ModuleDirs, ", "),
+		)
+	}
+
+	tw := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	for _, m := range modules {
+		active := " "
+		if m.name == s.cfg.Translation {
+			active = "*"
+		}
+		fmt.Fprintf(tw, "%s %s\t%s\t%s\n", active, m.name, moduleDescription(s, m.path), m.path)
+	}
+
+	return tw.Flush()
+}
+
+func runConfig(s *session, args []string) error {
+	return s.cfg.Write(os.Stdout)
+}
+
+func runHelp(s *session, args []string) error {
+	if len(args) < 1 {
+		printUsage()
+		return nil
+	}
+
+	c, ok := findCommand(args[0])
+	if !ok {
+		return fmt.Errorf("unknown command `%s`", args[0])
+	}
+
+	var o options
+	printCommandUsage(newFlagSet(c.name, &o, os.Stderr), c)
+
+	return nil
+}
@@ -0,0 +1,354 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/ButbkaDrug/bible/internal/config"
+)
+
+// flags that take a value, so the next word is not a part of the query
+var valueFlags = []string{
+	"t", "translation", "f", "format", "width", "limit",
+	"C", "context", "B", "before-context", "A", "after-context", "canon", "seed",
+}
+
+var shells = []string{"bash", "zsh", "fish"}
+
+// filters of `bible books`
+var bookKinds = []string{
+	"old", "new", "law", "history", "wisdom", "prophets", "gospels", "epistles", "apocalyptic",
+}
+
+const bashCompletion = `# bash completion for bible
+# source <(bible completion bash)
+_bible() {
+	local IFS=$'\n'
+	local candidates c
+	candidates=$(bible __complete "${COMP_WORDS[@]:1:COMP_CWORD}" 2>/dev/null)
+	COMPREPLY=()
+	for c in $candidates; do
+		COMPREPLY+=("$(printf '%q' "$c")")
+	done
+}
+complete -F _bible bible
+`
+
+const zshCompletion = `#compdef bible
+# zsh completion for bible
+# source <(bible completion zsh)
+_bible() {
+	local out
+	out=$(bible __complete "${(@)words[2,CURRENT]}" 2>/dev/null)
+	[[ -n $out ]] || return 1
+	compadd -U -- "${(@f)out}"
+}
+compdef _bible bible
+`
+
+const fishCompletion = `# fish completion for bible
+# bible completion fish > ~/.config/fish/completions/bible.fish
+complete -c bible -f -a '(bible __complete (commandline -opc)[2..-1] (commandline -ct))'
+`
+
+func runCompletion(s *session, args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("expected one of: %s", strings.Join(shells, ", "))
+	}
+
+	scripts := map[string]string{
+		"bash": bashCompletion,
+		"zsh":  zshCompletion,
+		"fish": fishCompletion,
+	}
+
+	script, ok := scripts[args[0]]
+	if !ok {
+		return fmt.Errorf("unknown shell `%s`, expected one of: %s", args[0], strings.Join(shells, ", "))
+	}
+
+	_, err := io.WriteString(os.Stdout, script)
+	return err
+}
+
+// called by the completion scripts with the words typed after `bible`.
+// The last word is the one being completed and can be empty.
+// Candidates are printed one per line, errors are never reported.
+func runComplete(s *session, args []string) error {
+	if len(args) < 1 {
+		args = []string{""}
+	}
+
+	// complete from the translation the command is going to use
+	for i, w := range args[:len(args)-1] {
+		if isFlag(w, "t", "translation") && i+1 < len(args)-1 {
+			s.cfg.Translation = args[i+1]
+		}
+	}
+
+	for _, c := range complete(s, args[:len(args)-1], args[len(args)-1]) {
+		fmt.Fprintln(os.Stdout, c)
+	}
+
+	return nil
+}
+
+func complete(s *session, prev []string, cur string) []string {
+	if len(prev) > 0 && isFlag(prev[len(prev)-1], "t", "translation") {
+		var names []string
+		for _, m := range listModules(s) {
+			names = append(names, m.name)
+		}
+		return withPrefix(names, cur)
+	}
+
+	if len(prev) > 0 && isFlag(prev[len(prev)-1], "canon") {
+		names := slices.Clone(config.SHIPPED_CANONS)
+		for name := range s.cfg.Canons {
+			names = append(names, name)
+		}
+		slices.Sort(names)
+		return withPrefix(names, cur)
+	}
+
+	if strings.HasPrefix(cur, "-") {
+		return withPrefix(flagNames(), cur)
+	}
+
+	positional := stripFlags(prev)
+
+	if len(positional) == 0 {
+		return append(withPrefix(commandNames(), cur), completeReference(s, positional, cur)...)
+	}
+
+	switch positional[0] {
+	case "read":
+		return completeReference(s, positional[1:], cur)
+	case "help":
+		if len(positional) == 1 {
+			return withPrefix(commandNames(), cur)
+		}
+	case "completion":
+		if len(positional) == 1 {
+			return withPrefix(shells, cur)
+		}
+	case "books":
+		return withPrefix(bookKinds, cur)
+	case "random", "votd":
+		return append(withPrefix(bookKinds, cur), completeReference(s, positional[1:], cur)...)
+	case "plan":
+		return completePlan(positional[1:], cur)
+	case "note", "tag", "highlight", "bookmark":
+		return completeAnnotation(s, positional, cur)
+	case "notes":
+		return completeReference(s, positional[1:], cur)
+	case "history":
+		if len(positional) == 1 {
+			return withPrefix(subcommandNames(historyCommands), cur)
+		}
+	case "filter":
+		if len(positional) == 1 {
+			return withPrefix([]string{FILTER_ANNOTATE, FILTER_EXPAND}, cur)
+		}
+	default:
+		if _, ok := findCommand(positional[0]); !ok {
+			return completeReference(s, positional, cur)
+		}
+	}
+
+	return nil
+}
+
+// offers books while the name is being typed and chapters once it is
+// complete. Book names can have several words, so `1 Co` is completed
+// to `1 Corinthians` by offering `Corinthians` for `Co`.
+func completeReference(s *session, positional []string, cur string) []string {
+	app, err := s.bible()
+	if err != nil {
+		return nil
+	}
+
+	typed := strings.Join(positional, " ")
+
+	if book, ok := app.LookupBook(typed); ok && typed != "" {
+		count, err := app.ChapterCount(book)
+		if err != nil {
+			return nil
+		}
+
+		var chapters []string
+		for i := 1; i <= count; i++ {
+			chapters = append(chapters, strconv.Itoa(i))
+		}
+		return withPrefix(chapters, cur)
+	}
+
+	books, err := app.GetBooks()
+	if err != nil {
+		return nil
+	}
+
+	var names []string
+	for _, b := range books {
+		names = append(names, b.LongName, b.ShortName)
+	}
+
+	return completeBookName(names, positional, cur)
+}
+
+func completeBookName(names []string, positional []string, cur string) []string {
+	var typed string
+	if len(positional) > 0 {
+		typed = strings.Join(positional, " ") + " "
+	}
+
+	var result []string
+	var seen = map[string]bool{}
+
+	for _, name := range names {
+		if !hasPrefixFold(name, typed+cur) {
+			continue
+		}
+
+		candidate := name[len(typed):]
+		if seen[candidate] {
+			continue
+		}
+		seen[candidate] = true
+
+		result = append(result, candidate)
+	}
+
+	return result
+}
+
+func hasPrefixFold(s, prefix string) bool {
+	return len(s) >= len(prefix) && strings.EqualFold(s[:len(prefix)], prefix)
+}
+
+func withPrefix(ss []string, prefix string) []string {
+	var result []string
+
+	for _, s := range ss {
+		if hasPrefixFold(s, prefix) {
+			result = append(result, s)
+		}
+	}
+
+	return result
+}
+
+func commandNames() []string {
+	var names []string
+
+	for _, c := range commands {
+		if !strings.HasPrefix(c.name, "__") {
+			names = append(names, c.name)
+		}
+	}
+
+	return names
+}
+
+func flagNames() []string {
+	var names []string
+	var o options
+
+	newFlagSet("", &o, os.Stderr).VisitAll(func(f *flag.Flag) {
+		prefix := "--"
+		if len(f.Name) == 1 {
+			prefix = "-"
+		}
+		names = append(names, prefix+f.Name)
+	})
+
+	return names
+}
+
+func isFlag(word string, names ...string) bool {
+	name := strings.TrimLeft(word, "-")
+
+	if name == word || strings.Contains(name, "=") {
+		return false
+	}
+
+	for _, n := range names {
+		if n == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+// removes flags and their values from the words
+func stripFlags(words []string) []string {
+	var result []string
+
+	for i := 0; i < len(words); i++ {
+		w := words[i]
+
+		if w == "--" {
+			return append(result, words[i+1:]...)
+		}
+
+		if !strings.HasPrefix(w, "-") || w == "-" {
+			result = append(result, w)
+			continue
+		}
+
+		if isFlag(w, valueFlags...) {
+			i++
+		}
+	}
+
+	return result
+}
+
+// plan commands first, then names of plans
+func completePlan(args []string, cur string) []string {
+	if len(args) == 0 {
+		return withPrefix(subcommandNames(planCommands), cur)
+	}
+
+	if len(args) > 1 {
+		return nil
+	}
+
+	plans, err := allPlans()
+	if err != nil {
+		return nil
+	}
+
+	var names []string
+	for _, p := range plans {
+		names = append(names, p.Name)
+	}
+
+	return withPrefix(names, cur)
+}
+
+// subcommands first, then the reference they go on
+func completeAnnotation(s *session, positional []string, cur string) []string {
+	var commands = map[string][]command{
+		"note":      noteCommands,
+		"tag":       tagCommands,
+		"highlight": highlightCommands,
+		"bookmark":  bookmarkCommands,
+	}[positional[0]]
+
+	if len(positional) == 1 {
+		return withPrefix(subcommandNames(commands), cur)
+	}
+
+	if positional[1] == "list" || positional[0] == "note" && positional[1] == "rm" {
+		return nil
+	}
+
+	return completeReference(s, positional[2:], cur)
+}
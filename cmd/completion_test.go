@@ -0,0 +1,63 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCompleteBookName(t *testing.T) {
+	names := []string{"1 Corinthians", "1Cor", "Colossians", "Col", "Song of Solomon", "Song"}
+
+	tests := []struct {
+		positional []string
+		cur        string
+	}{
+		{positional: []string{"1"}, cur: "Co"},
+		{positional: nil, cur: "co"},
+		{positional: nil, cur: "1c"},
+		{positional: []string{"Song", "of"}, cur: ""},
+		{positional: []string{"2"}, cur: ""},
+	}
+
+	expectedResults := []string{
+		"Corinthians",
+		"Colossians,Col",
+		"1Cor",
+		"Solomon",
+		"",
+	}
+
+	for i, test := range tests {
+		result := strings.Join(completeBookName(names, test.positional, test.cur), ",")
+		expect := expectedResults[i]
+
+		if result != expect {
+			t.Fatalf("TEST[%d] failed: expected %q got %q", i, expect, result)
+		}
+	}
+}
+
+func TestStripFlags(t *testing.T) {
+	tests := [][]string{
+		{"-t", "NIV", "john", "3"},
+		{"john", "--width", "40", "--no-color", "3"},
+		{"--format=json", "john"},
+		{"--", "-t", "john"},
+	}
+
+	expectedResults := []string{
+		"john 3",
+		"john 3",
+		"john",
+		"-t john",
+	}
+
+	for i, test := range tests {
+		result := strings.Join(stripFlags(test), " ")
+		expect := expectedResults[i]
+
+		if result != expect {
+			t.Fatalf("TEST[%d] failed: expected %q got %q", i, expect, result)
+		}
+	}
+}
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/ButbkaDrug/bible"
+)
+
+const (
+	FILTER_ANNOTATE = "annotate"
+	FILTER_EXPAND   = "expand"
+)
+
+// reads text from stdin and adds verse text to every reference found in
+// it. annotate keeps the reference and puts the text after it in
+// brackets, expand replaces the reference with the quoted text
+func runFilter(s *session, args []string) error {
+	mode := FILTER_ANNOTATE
+	if len(args) > 0 {
+		mode = args[0]
+	}
+
+	if mode != FILTER_ANNOTATE && mode != FILTER_EXPAND {
+		return fmt.Errorf("unknown filter mode `%s`, expected %s or %s", mode, FILTER_ANNOTATE, FILTER_EXPAND)
+	}
+
+	app, err := s.bible()
+	if err != nil {
+		return err
+	}
+
+	input, err := io.ReadAll(os.Stdin)
+	if err != nil {
+		return err
+	}
+
+	out, err := filterText(app, string(input), mode)
+	if err != nil {
+		return err
+	}
+
+	_, err = io.WriteString(os.Stdout, out)
+
+	return err
+}
+
+func filterText(app *bible.Bible, text, mode string) (string, error) {
+	var out = new(strings.Builder)
+	var last int
+
+	for _, c := range app.ExtractReferences(text) {
+		verses, err := app.GetPassages(bible.NewPassageSet(c.Passages...))
+		if err != nil {
+			return "", err
+		}
+
+		// a reference to a verse the module does not have stays as is
+		if len(verses) < 1 {
+			continue
+		}
+
+		var lines = make([]string, len(verses))
+		for i, v := range verses {
+			lines[i] = v.PlainText()
+		}
+		quote := strings.Join(lines, " ")
+
+		out.WriteString(text[last:c.Start])
+
+		switch mode {
+		case FILTER_EXPAND:
+			fmt.Fprintf(out, "“%s” (%s)", quote, c.Text)
+		default:
+			fmt.Fprintf(out, "%s [%s]", c.Text, quote)
+		}
+
+		last = c.End
+	}
+
+	out.WriteString(text[last:])
+
+	return out.String(), nil
+}
@@ -0,0 +1,148 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+
+	"github.com/ButbkaDrug/bible/internal/config"
+)
+
+// options shared by all the commands. They take precedence over the
+// environment and the config file
+type options struct {
+	translation string
+	format      string
+	noColor     bool
+	width       int
+	limit       int
+	context     int
+	before      int
+	after       int
+	interactive bool
+	all         bool
+	canon       string
+	passage     bool
+	seed        string
+	annotations bool
+
+	set map[string]bool
+}
+
+func newFlagSet(name string, o *options, w io.Writer) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(w)
+
+	fs.StringVar(&o.translation, "t", "", "translation to use, e.g. ESV")
+	fs.StringVar(&o.translation, "translation", "", "translation to use, e.g. ESV")
+	fs.StringVar(&o.format, "f", "", "output format: text or json")
+	fs.StringVar(&o.format, "format", "", "output format: text or json")
+	fs.BoolVar(&o.noColor, "no-color", false, "disable colors")
+	fs.IntVar(&o.width, "width", 0, "wrap lines at `columns`, -1 disables wrapping")
+	fs.IntVar(&o.limit, "limit", 0, "print at most `n` verses")
+	fs.IntVar(&o.context, "C", 0, "show `n` verses around every verse")
+	fs.IntVar(&o.context, "context", 0, "show `n` verses around every verse")
+	fs.IntVar(&o.before, "B", 0, "show `n` verses before every verse")
+	fs.IntVar(&o.before, "before-context", 0, "show `n` verses before every verse")
+	fs.IntVar(&o.after, "A", 0, "show `n` verses after every verse")
+	fs.IntVar(&o.after, "after-context", 0, "show `n` verses after every verse")
+	fs.BoolVar(&o.interactive, "i", false, "read queries one per line")
+	fs.BoolVar(&o.interactive, "interactive", false, "read queries one per line")
+	fs.BoolVar(&o.all, "all", false, "read whole books instead of listing chapters")
+	fs.BoolVar(&o.passage, "passage", false, "random and votd pick whole passages instead of verses")
+	fs.StringVar(&o.seed, "seed", "", "verse of the day `seed`, the same seed gives the same verse")
+	fs.BoolVar(&o.annotations, "annotations", false, "show your highlights and mark verses with notes")
+	fs.StringVar(&o.canon, "canon", "", "list and search books of the `canon`: protestant, catholic, orthodox")
+
+	return fs
+}
+
+// unlike flag.Parse flags can go before and after positional arguments,
+// so `bible john 3:16 -t NIV` works the same as `bible -t NIV john 3:16`.
+// Everything after `--` is positional.
+func parseFlags(fs *flag.FlagSet, o *options, args []string) ([]string, error) {
+	var positional []string
+
+	for {
+		if err := fs.Parse(args); err != nil {
+			return nil, err
+		}
+
+		consumed := args[:len(args)-fs.NArg()]
+		args = fs.Args()
+
+		if len(consumed) > 0 && consumed[len(consumed)-1] == "--" {
+			positional = append(positional, args...)
+			break
+		}
+
+		if len(args) < 1 {
+			break
+		}
+
+		positional = append(positional, args[0])
+		args = args[1:]
+	}
+
+	o.set = map[string]bool{}
+	fs.Visit(func(f *flag.Flag) {
+		o.set[f.Name] = true
+	})
+
+	return positional, nil
+}
+
+// -C sets both sides of the context, -B and -A win over it
+func (o options) contextVerses() (before, after int) {
+	before, after = o.context, o.context
+
+	if o.set["B"] || o.set["before-context"] {
+		before = o.before
+	}
+
+	if o.set["A"] || o.set["after-context"] {
+		after = o.after
+	}
+
+	return before, after
+}
+
+func (o options) apply(cfg *config.Config) error {
+	if o.set["t"] || o.set["translation"] {
+		cfg.Translation = o.translation
+	}
+
+	if o.set["f"] || o.set["format"] {
+		cfg.Format = o.format
+	}
+
+	if o.set["seed"] {
+		cfg.Seed = o.seed
+	}
+
+	if o.set["annotations"] {
+		cfg.Annotations = o.annotations
+	}
+
+	if o.set["canon"] {
+		cfg.Canon = o.canon
+	}
+
+	if o.noColor {
+		cfg.Color = "none"
+	}
+
+	if o.set["width"] {
+		cfg.Width = o.width
+	}
+
+	if o.limit < 0 {
+		return fmt.Errorf("--limit cannot be negative")
+	}
+
+	if o.context < 0 || o.before < 0 || o.after < 0 {
+		return fmt.Errorf("context cannot be negative")
+	}
+
+	return nil
+}
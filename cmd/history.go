@@ -0,0 +1,266 @@
+package main
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	"github.com/ButbkaDrug/bible"
+	"github.com/ButbkaDrug/bible/internal/state"
+)
+
+// number of lookups `bible history` lists and chapters `history top`
+// shows without --limit
+const (
+	HISTORY_LIMIT = 20
+	TOP_LIMIT     = 10
+)
+
+var historyCommands []command
+
+func init() {
+	historyCommands = []command{
+		{"list", "", "list recent lookups, newest first. This is the default", historyList},
+		{"run", "<id>", "read the lookup again", historyRun},
+		{"top", "", "list chapters you read most", historyTop},
+		{"clear", "", "forget every lookup", historyClear},
+	}
+}
+
+// `bible history 12` is the same as `bible history run 12`
+func runHistory(s *session, args []string) error {
+	if len(args) > 0 {
+		if _, err := strconv.Atoi(args[0]); err == nil {
+			return historyRun(s, args)
+		}
+	}
+
+	return runSubcommand(s, "history", historyCommands, "list", args)
+}
+
+// reads the last lookup again, flags can change the format or the
+// translation
+func runLast(s *session, args []string) error {
+	store, err := s.state()
+	if err != nil {
+		return err
+	}
+
+	rows, err := store.ListHistory(s.ctx, 1)
+	if err != nil {
+		return err
+	}
+
+	if len(rows) < 1 {
+		return errors.New("history is empty")
+	}
+
+	return s.rerun(rows[0])
+}
+
+// keeps the lookup in the state database. Reading goes on when it
+// can't be written, so only a warning is printed
+func (s *session) recordLookup(lookup bible.Lookup) {
+	if err := s.addHistory(lookup); err != nil {
+		fmt.Fprintf(os.Stderr, "history: %s\n", err)
+	}
+}
+
+func (s *session) addHistory(lookup bible.Lookup) error {
+	store, err := s.state()
+	if err != nil {
+		return err
+	}
+
+	id, err := store.AddHistory(s.ctx, state.AddHistoryParams{
+		Query:       lookup.Query,
+		Reference:   lookup.Reference(),
+		Translation: s.cfg.Translation,
+		CreatedAt:   time.Now().Format(time.DateTime),
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, c := range lookup.Chapters {
+		err := store.AddHistoryChapter(s.ctx, state.AddHistoryChapterParams{
+			History: id,
+			Book:    int64(c.BookNumber),
+			Chapter: int64(c.Chapter),
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// reads the lookup in the translation it was read in, unless another
+// one was picked with a flag
+func (s *session) rerun(h state.History) error {
+	if !s.pickedTranslation && h.Translation != s.cfg.Translation {
+		if err := s.reopen(h.Translation); err != nil {
+			return err
+		}
+	}
+
+	app, err := s.bible()
+	if err != nil {
+		return err
+	}
+
+	// books are only in the history when they were read whole
+	return app.SetWholeBooks(true).SetQuery(h.Reference).Run()
+}
+
+func (s *session) historyLimit(def int) int64 {
+	if s.limit > 0 {
+		return int64(s.limit)
+	}
+
+	return int64(def)
+}
+
+type jsonHistory struct {
+	ID          int64  `json:"id"`
+	Query       string `json:"query"`
+	Reference   string `json:"reference"`
+	Translation string `json:"translation"`
+	CreatedAt   string `json:"created_at"`
+}
+
+func historyList(s *session, args []string) error {
+	store, err := s.state()
+	if err != nil {
+		return err
+	}
+
+	rows, err := store.ListHistory(s.ctx, s.historyLimit(HISTORY_LIMIT))
+	if err != nil {
+		return err
+	}
+
+	if len(rows) < 1 {
+		return errors.New("history is empty")
+	}
+
+	if s.cfg.Format == "json" {
+		var out = make([]jsonHistory, len(rows))
+
+		for i, row := range rows {
+			out[i] = jsonHistory(row)
+		}
+
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+
+		return encoder.Encode(out)
+	}
+
+	tw := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintf(tw, "ID\tRead\tTranslation\tReference\n")
+
+	for _, row := range rows {
+		fmt.Fprintf(tw, "%d\t%s\t%s\t%s\n", row.ID, row.CreatedAt, row.Translation, row.Reference)
+	}
+
+	return tw.Flush()
+}
+
+func historyRun(s *session, args []string) error {
+	if len(args) < 1 {
+		return errors.New("name the id of the lookup, see `bible history`")
+	}
+
+	id, err := strconv.ParseInt(args[0], 10, 64)
+	if err != nil {
+		return fmt.Errorf("lookup id must be a number: %w", err)
+	}
+
+	store, err := s.state()
+	if err != nil {
+		return err
+	}
+
+	row, err := store.GetHistory(s.ctx, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("there is no lookup %d in the history", id)
+	}
+	if err != nil {
+		return err
+	}
+
+	return s.rerun(row)
+}
+
+type jsonChapter struct {
+	Reference  string `json:"reference"`
+	BookNumber int    `json:"book_number"`
+	Chapter    int    `json:"chapter"`
+	Reads      int64  `json:"reads"`
+}
+
+// chapters counted once for every lookup that had verses of them
+func historyTop(s *session, args []string) error {
+	store, err := s.state()
+	if err != nil {
+		return err
+	}
+
+	rows, err := store.GetTopChapters(s.ctx, s.historyLimit(TOP_LIMIT))
+	if err != nil {
+		return err
+	}
+
+	if len(rows) < 1 {
+		return errors.New("history is empty")
+	}
+
+	var out = make([]jsonChapter, len(rows))
+
+	for i, row := range rows {
+		ref := bible.Reference{BookNumber: int(row.Book), Chapter: int(row.Chapter)}
+
+		out[i] = jsonChapter{
+			Reference:  ref.String(),
+			BookNumber: ref.BookNumber,
+			Chapter:    ref.Chapter,
+			Reads:      row.Count,
+		}
+	}
+
+	if s.cfg.Format == "json" {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+
+		return encoder.Encode(out)
+	}
+
+	tw := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintf(tw, "Chapter\tReads\n")
+
+	for _, c := range out {
+		fmt.Fprintf(tw, "%s\t%d\n", c.Reference, c.Reads)
+	}
+
+	return tw.Flush()
+}
+
+func historyClear(s *session, args []string) error {
+	store, err := s.state()
+	if err != nil {
+		return err
+	}
+
+	if err := store.ClearHistoryChapters(s.ctx); err != nil {
+		return err
+	}
+
+	return store.ClearHistory(s.ctx)
+}
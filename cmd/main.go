@@ -1,60 +1,109 @@
 package main
 
 import (
-	"context"
-	"database/sql"
+	"errors"
+	"flag"
 	"fmt"
+	"io"
 	"log"
 	"os"
-	"path/filepath"
+	"slices"
 	"strings"
-	"time"
 
 	"github.com/ButbkaDrug/bible"
-	_ "modernc.org/sqlite"
 )
 
 func main() {
+	if err := run(os.Args[1:]); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			os.Exit(2)
+		}
+		log.Print(err)
+		printCaret(os.Stderr, err)
+		os.Exit(1)
+	}
+}
 
-	HOME, err := os.UserHomeDir()
-	BIBLE_DIR := filepath.Join(HOME, ".config", "bible-cli")
-	TRANSLATION := "ESV"
-	EXT := "SQLite3"
-
-	if err != nil {
-		log.Fatal("failed to find home directory!")
+// will show the query of a parse error with a caret under the place
+// that could not be read
+func printCaret(w io.Writer, err error) {
+	var parseErr *bible.ParseError
+	if !errors.As(err, &parseErr) {
+		return
 	}
 
-	if env_path := os.Getenv("BIBLECLI"); env_path != "" {
-		BIBLE_DIR = env_path
+	fmt.Fprintf(w, "  %s\n  %s^\n", parseErr.Query, strings.Repeat(" ", parseErr.Col()-1))
+}
+
+// first argument picks the command, flags may go before it. When there
+// is no name of a command all the arguments are a query for `read`, so
+// `bible john 3:16` keeps working.
+func run(args []string) error {
+	var name = "read"
+
+	if len(args) > 0 && slices.Contains([]string{"-h", "-help", "--help"}, args[0]) {
+		printUsage()
+		return nil
 	}
 
-	if env_translation := os.Getenv("TRANSLATION"); env_translation != "" {
-		TRANSLATION = env_translation
+	if len(args) > 0 {
+		if _, ok := findCommand(args[0]); ok {
+			name = args[0]
+			args = args[1:]
+		} else if i := commandAfterFlags(args); i > 0 {
+			name = args[i]
+			args = slices.Delete(slices.Clone(args), i, i+1)
+		}
 	}
 
-	DATABASE := filepath.Join(BIBLE_DIR, fmt.Sprintf("%s.%s", TRANSLATION, EXT))
+	cmd, _ := findCommand(name)
+
+	var o options
+	var err error
+	fs := newFlagSet(cmd.name, &o, os.Stderr)
+	fs.Usage = func() { printCommandUsage(fs, cmd) }
 
-	conn, err := sql.Open("sqlite", DATABASE)
+	// words for completion can be anything, including unfinished flags
+	var positional = args
+
+	if cmd.name != "__complete" {
+		positional, err = parseFlags(fs, &o, args)
+		if err != nil {
+			return err
+		}
+	}
+
+	s, err := newSession(o)
 	if err != nil {
-		log.Fatalf("database connection error: %s", err)
+		return err
+	}
+	defer s.close()
+
+	if o.interactive {
+		return runREPL(s, positional)
 	}
-	defer conn.Close()
 
-	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
-	defer cancel()
+	return cmd.run(s, positional)
+}
 
-	var env = os.Getenv("BIBLE_ENV")
+// position of a command that follows flags, as in `bible -t NIV books`,
+// or 0 when there is none. Words after `--` are never a command
+func commandAfterFlags(args []string) int {
+	var o options
+	fs := newFlagSet("", &o, io.Discard)
 
-	var query string
-	if len(os.Args) > 1 {
-		query = strings.Join(os.Args[1:], " ")
+	if err := fs.Parse(args); err != nil || fs.NArg() < 1 {
+		return 0
 	}
 
-	app := bible.New(ctx, conn, env).
-		SetQuery(query)
+	i := len(args) - fs.NArg()
+	if i < 1 || args[i-1] == "--" {
+		return 0
+	}
 
-	if err := app.Run(); err != nil {
-		log.Fatal(err)
+	if _, ok := findCommand(args[i]); !ok {
+		return 0
 	}
+
+	return i
 }
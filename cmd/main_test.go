@@ -0,0 +1,46 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/ButbkaDrug/bible"
+)
+
+func TestCommandAfterFlags(t *testing.T) {
+	tests := [][]string{
+		{"--no-color", "history"},
+		{"-t", "NIV", "books"},
+		{"--format=json", "--limit", "5", "history", "top"},
+		{"-t", "NIV", "john", "3"},
+		{"history"},
+		{"--", "history"},
+		{"-t", "NIV", "--", "books"},
+		{"--unknown", "books"},
+	}
+
+	expectedResults := []int{1, 2, 3, 0, 0, 0, 0, 0}
+
+	for i, test := range tests {
+		if result := commandAfterFlags(test); result != expectedResults[i] {
+			t.Fatalf("TEST[%d] failed: expected %d got %d", i, expectedResults[i], result)
+		}
+	}
+}
+
+func TestColorModeNoColor(t *testing.T) {
+	t.Setenv("NO_COLOR", "1")
+
+	tests := []string{"auto", "", "256", "16", "none"}
+	expectedResults := []bible.ColorMode{bible.NO_COLOR, bible.NO_COLOR, bible.COLOR_256, bible.COLOR_16, bible.NO_COLOR}
+
+	for i, test := range tests {
+		result, err := colorMode(test)
+		if err != nil {
+			t.Fatalf("TEST[%d] failed: %s", i, err)
+		}
+
+		if result != expectedResults[i] {
+			t.Fatalf("TEST[%d] failed: expected %v got %v", i, expectedResults[i], result)
+		}
+	}
+}
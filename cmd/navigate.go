@@ -0,0 +1,60 @@
+package main
+
+import (
+	"errors"
+
+	"github.com/ButbkaDrug/bible"
+	"github.com/ButbkaDrug/bible/internal/repository"
+)
+
+// keeps books and chapters of the module at hand. Interactive commands
+// come back to the same chapters all the time.
+type navigator struct {
+	app      *bible.Bible
+	books    []repository.Book
+	chapters map[location][]bible.Verse
+}
+
+func newNavigator(app *bible.Bible) (*navigator, error) {
+	books, err := app.GetBooks()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(books) < 1 {
+		return nil, errors.New("translation has no books")
+	}
+
+	return &navigator{
+		app:      app,
+		books:    books,
+		chapters: map[location][]bible.Verse{},
+	}, nil
+}
+
+func (n *navigator) bookIndex(number int) int {
+	for i, b := range n.books {
+		if int(b.BookNumber) == number {
+			return i
+		}
+	}
+
+	return -1
+}
+
+func (n *navigator) chapter(book, chapter int) ([]bible.Verse, error) {
+	key := location{book: book, chapter: chapter}
+
+	if verses, ok := n.chapters[key]; ok {
+		return verses, nil
+	}
+
+	verses, err := n.app.GetChapter(book, chapter)
+	if err != nil {
+		return nil, err
+	}
+
+	n.chapters[key] = verses
+
+	return verses, nil
+}
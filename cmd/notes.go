@@ -0,0 +1,581 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/ButbkaDrug/bible"
+	"github.com/ButbkaDrug/bible/internal/state"
+)
+
+// style of highlights added without one
+const DEFAULT_HIGHLIGHT = "yellow"
+
+var noteCommands, tagCommands, highlightCommands, bookmarkCommands []command
+
+func init() {
+	noteCommands = []command{
+		{"add", "<reference> <text>", "add a note to the passage", noteAdd},
+		{"list", "[reference]", "list notes, of the passage when it is given. This is the default", noteList},
+		{"rm", "<id>", "remove the note", noteRemove},
+	}
+
+	tagCommands = []command{
+		{"add", "<reference> <tag>...", "tag the passage", tagAdd},
+		{"list", "[tag]", "list tags or passages with the tag. This is the default", tagList},
+		{"rm", "<reference> <tag>...", "remove tags from passages that overlap the reference", tagRemove},
+	}
+
+	highlightCommands = []command{
+		{"add", "<reference> [style]", "highlight the passage, " + DEFAULT_HIGHLIGHT + " by default", highlightAdd},
+		{"list", "", "list highlights. This is the default", highlightList},
+		{"rm", "<reference>", "remove highlights that overlap the reference", highlightRemove},
+	}
+
+	bookmarkCommands = []command{
+		{"add", "<reference>", "bookmark the passage", bookmarkAdd},
+		{"list", "", "list bookmarks. This is the default", bookmarkList},
+		{"rm", "<reference>", "remove bookmarks that overlap the reference", bookmarkRemove},
+	}
+}
+
+func runNote(s *session, args []string) error {
+	return runSubcommand(s, "note", noteCommands, "list", args)
+}
+
+func runTag(s *session, args []string) error {
+	return runSubcommand(s, "tag", tagCommands, "list", args)
+}
+
+func runHighlight(s *session, args []string) error {
+	return runSubcommand(s, "highlight", highlightCommands, "list", args)
+}
+
+func runBookmark(s *session, args []string) error {
+	return runSubcommand(s, "bookmark", bookmarkCommands, "list", args)
+}
+
+// annotations of the state database the way the bible package reads
+// them. Tags and bookmarks are not shown with the text
+type annotations struct {
+	store *state.Queries
+}
+
+func (a annotations) BookAnnotations(ctx context.Context, book int) ([]bible.Annotation, error) {
+	rows, err := a.store.GetBookAnnotations(ctx, state.GetBookAnnotationsParams{
+		FromBook: int64(book),
+		ToBook:   int64(book),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var result []bible.Annotation
+
+	for _, row := range rows {
+		switch row.Kind {
+		case state.KIND_HIGHLIGHT:
+			result = append(result, bible.Annotation{Passage: passageOf(row), Highlight: row.Value})
+		case state.KIND_NOTE:
+			result = append(result, bible.Annotation{Passage: passageOf(row), Note: row.Value})
+		}
+	}
+
+	return result, nil
+}
+
+func passageOf(row state.Annotation) bible.Passage {
+	return bible.Passage{
+		Start: bible.Reference{BookNumber: int(row.FromBook), Chapter: int(row.FromChapter), Verse: int(row.FromVerse)},
+		End:   bible.Reference{BookNumber: int(row.ToBook), Chapter: int(row.ToChapter), Verse: int(row.ToVerse)},
+	}
+}
+
+// splits arguments into passages they start with and the rest. The
+// longest beginning that is a reference wins, so in `John 3:16 God so
+// loved` the words are the rest
+func (s *session) splitReference(args []string) ([]bible.Passage, []string, error) {
+	app, err := s.bible()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for i := len(args); i > 0; i-- {
+		passages, err := app.Resolve(strings.Join(args[:i], " "))
+		if err == nil && len(passages) > 0 {
+			return passages, args[i:], nil
+		}
+	}
+
+	if len(args) < 1 {
+		return nil, nil, errors.New("expected a reference")
+	}
+
+	return nil, nil, fmt.Errorf("`%s` does not start with a reference", strings.Join(args, " "))
+}
+
+func (s *session) resolve(args []string) ([]bible.Passage, error) {
+	app, err := s.bible()
+	if err != nil {
+		return nil, err
+	}
+
+	return app.Resolve(strings.Join(args, " "))
+}
+
+// adds an annotation of the kind to every passage. The same annotation
+// on the same passage is replaced, so it is never there twice. Passages
+// may have one highlight and one bookmark, but many notes and tags
+func (s *session) annotate(kind string, passages []bible.Passage, value string) ([]int64, error) {
+	store, err := s.state()
+	if err != nil {
+		return nil, err
+	}
+
+	var ids []int64
+
+	for _, p := range passages {
+		switch kind {
+		case state.KIND_HIGHLIGHT, state.KIND_BOOKMARK:
+			_, err = store.DeleteAnnotationsAt(s.ctx, state.DeleteAnnotationsAtParams{
+				Kind:        kind,
+				FromBook:    int64(p.Start.BookNumber),
+				FromChapter: int64(p.Start.Chapter),
+				FromVerse:   int64(p.Start.Verse),
+				ToBook:      int64(p.End.BookNumber),
+				ToChapter:   int64(p.End.Chapter),
+				ToVerse:     int64(p.End.Verse),
+			})
+		case state.KIND_TAG:
+			_, err = store.DeleteAnnotationValue(s.ctx, state.DeleteAnnotationValueParams{
+				Kind:        kind,
+				FromBook:    int64(p.Start.BookNumber),
+				FromChapter: int64(p.Start.Chapter),
+				FromVerse:   int64(p.Start.Verse),
+				ToBook:      int64(p.End.BookNumber),
+				ToChapter:   int64(p.End.Chapter),
+				ToVerse:     int64(p.End.Verse),
+				Value:       value,
+			})
+		}
+		if err != nil {
+			return ids, err
+		}
+
+		id, err := store.AddAnnotation(s.ctx, state.AddAnnotationParams{
+			Kind:        kind,
+			FromBook:    int64(p.Start.BookNumber),
+			FromChapter: int64(p.Start.Chapter),
+			FromVerse:   int64(p.Start.Verse),
+			ToBook:      int64(p.End.BookNumber),
+			ToChapter:   int64(p.End.Chapter),
+			ToVerse:     int64(p.End.Verse),
+			Value:       value,
+			CreatedAt:   time.Now().Format(time.DateTime),
+		})
+		if err != nil {
+			return ids, err
+		}
+
+		ids = append(ids, id)
+	}
+
+	return ids, nil
+}
+
+// annotations of the kind that overlap any of the passages. No passages
+// is all of them
+func (s *session) annotationsOf(kind string, passages []bible.Passage) ([]state.Annotation, error) {
+	store, err := s.state()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := store.ListAnnotations(s.ctx, kind)
+	if err != nil || len(passages) < 1 {
+		return rows, err
+	}
+
+	var result []state.Annotation
+
+	for _, row := range rows {
+		for _, p := range passages {
+			if passageOf(row).Overlaps(p) {
+				result = append(result, row)
+				break
+			}
+		}
+	}
+
+	return result, nil
+}
+
+// removes annotations of the kind that overlap the passages. When keep
+// is set only the ones it returns false for are removed
+func (s *session) removeAnnotations(kind string, passages []bible.Passage, keep func(state.Annotation) bool) (int, error) {
+	if len(passages) < 1 {
+		return 0, errors.New("expected a reference")
+	}
+
+	rows, err := s.annotationsOf(kind, passages)
+	if err != nil {
+		return 0, err
+	}
+
+	store, err := s.state()
+	if err != nil {
+		return 0, err
+	}
+
+	var count int
+
+	for _, row := range rows {
+		if keep != nil && keep(row) {
+			continue
+		}
+
+		if _, err := store.DeleteAnnotation(s.ctx, state.DeleteAnnotationParams{ID: row.ID, Kind: kind}); err != nil {
+			return count, err
+		}
+		count++
+	}
+
+	return count, nil
+}
+
+type jsonAnnotation struct {
+	ID        int64  `json:"id"`
+	Kind      string `json:"kind"`
+	Reference string `json:"reference"`
+	Value     string `json:"value,omitempty"`
+	CreatedAt string `json:"created_at"`
+}
+
+// prints annotations as a table or as JSON. Value is the last column
+// and is left out when its header is empty
+func (s *session) printAnnotations(rows []state.Annotation, valueHeader string) error {
+	if s.cfg.Format == "json" {
+		var out = make([]jsonAnnotation, len(rows))
+
+		for i, row := range rows {
+			out[i] = jsonAnnotation{
+				ID:        row.ID,
+				Kind:      row.Kind,
+				Reference: passageOf(row).String(),
+				Value:     row.Value,
+				CreatedAt: row.CreatedAt,
+			}
+		}
+
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+
+		return encoder.Encode(out)
+	}
+
+	tw := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+
+	header := "ID\tReference\tAdded"
+	if valueHeader != "" {
+		header += "\t" + valueHeader
+	}
+	fmt.Fprintln(tw, header)
+
+	for _, row := range rows {
+		added, _, _ := strings.Cut(row.CreatedAt, " ")
+		line := fmt.Sprintf("%d\t%s\t%s", row.ID, passageOf(row), added)
+
+		if valueHeader != "" {
+			line += "\t" + row.Value
+		}
+		fmt.Fprintln(tw, line)
+	}
+
+	return tw.Flush()
+}
+
+func noteAdd(s *session, args []string) error {
+	passages, rest, err := s.splitReference(args)
+	if err != nil {
+		return err
+	}
+
+	if len(passages) > 1 {
+		return errors.New("a note goes on one passage, e.g. `John 3:16-18`")
+	}
+
+	text := strings.TrimSpace(strings.Join(rest, " "))
+	if text == "" {
+		return errors.New("the note has no text")
+	}
+
+	ids, err := s.annotate(state.KIND_NOTE, passages, text)
+	printAdded(state.KIND_NOTE, ids, passages)
+
+	return err
+}
+
+// prints what was added, so it can be removed by its id
+func printAdded(kind string, ids []int64, passages []bible.Passage) {
+	for i, id := range ids {
+		fmt.Printf("%s %d on %s\n", kind, id, passages[i])
+	}
+}
+
+// `bible notes John 3` lists notes on any verse of John 3
+func noteList(s *session, args []string) error {
+	var passages []bible.Passage
+
+	if len(args) > 0 {
+		var err error
+		if passages, err = s.resolve(args); err != nil {
+			return err
+		}
+	}
+
+	rows, err := s.annotationsOf(state.KIND_NOTE, passages)
+	if err != nil {
+		return err
+	}
+
+	if len(rows) < 1 {
+		return errors.New("no notes found")
+	}
+
+	return s.printAnnotations(rows, "Note")
+}
+
+func noteRemove(s *session, args []string) error {
+	if len(args) < 1 {
+		return errors.New("name the id of the note, see `bible notes`")
+	}
+
+	store, err := s.state()
+	if err != nil {
+		return err
+	}
+
+	for _, arg := range args {
+		id, err := strconv.ParseInt(arg, 10, 64)
+		if err != nil {
+			return fmt.Errorf("note id must be a number: %w", err)
+		}
+
+		n, err := store.DeleteAnnotation(s.ctx, state.DeleteAnnotationParams{ID: id, Kind: state.KIND_NOTE})
+		if err != nil {
+			return err
+		}
+
+		if n < 1 {
+			return fmt.Errorf("there is no note %d", id)
+		}
+	}
+
+	return nil
+}
+
+func tagAdd(s *session, args []string) error {
+	passages, tags, err := s.splitReference(args)
+	if err != nil {
+		return err
+	}
+
+	if len(tags) < 1 {
+		return errors.New("name the tags to add")
+	}
+
+	for _, tag := range tags {
+		tag = strings.ToLower(tag)
+
+		ids, err := s.annotate(state.KIND_TAG, passages, tag)
+		printAdded(fmt.Sprintf("%s `%s`", state.KIND_TAG, tag), ids, passages)
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// tags with the number of passages they are on. With a tag the passages
+// are listed instead
+func tagList(s *session, args []string) error {
+	store, err := s.state()
+	if err != nil {
+		return err
+	}
+
+	if len(args) > 0 {
+		rows, err := store.ListAnnotationsByValue(s.ctx, state.ListAnnotationsByValueParams{
+			Kind:  state.KIND_TAG,
+			Value: strings.ToLower(strings.Join(args, " ")),
+		})
+		if err != nil {
+			return err
+		}
+
+		if len(rows) < 1 {
+			return fmt.Errorf("nothing is tagged `%s`", strings.Join(args, " "))
+		}
+
+		return s.printAnnotations(rows, "")
+	}
+
+	tags, err := store.ListTags(s.ctx)
+	if err != nil {
+		return err
+	}
+
+	if len(tags) < 1 {
+		return errors.New("no tags found")
+	}
+
+	if s.cfg.Format == "json" {
+		var out = make(map[string]int64, len(tags))
+		for _, t := range tags {
+			out[t.Value] = t.Count
+		}
+
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+
+		return encoder.Encode(out)
+	}
+
+	tw := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintf(tw, "Tag\tPassages\n")
+
+	for _, t := range tags {
+		fmt.Fprintf(tw, "%s\t%d\n", t.Value, t.Count)
+	}
+
+	return tw.Flush()
+}
+
+func tagRemove(s *session, args []string) error {
+	passages, tags, err := s.splitReference(args)
+	if err != nil {
+		return err
+	}
+
+	if len(tags) < 1 {
+		return errors.New("name the tags to remove")
+	}
+
+	for i := range tags {
+		tags[i] = strings.ToLower(tags[i])
+	}
+
+	n, err := s.removeAnnotations(state.KIND_TAG, passages, func(row state.Annotation) bool {
+		return !slices.Contains(tags, row.Value)
+	})
+	if err != nil {
+		return err
+	}
+
+	if n < 1 {
+		return fmt.Errorf("%s has no such tags", bible.NewPassageSet(passages...))
+	}
+
+	return nil
+}
+
+func highlightAdd(s *session, args []string) error {
+	passages, rest, err := s.splitReference(args)
+	if err != nil {
+		return err
+	}
+
+	style := DEFAULT_HIGHLIGHT
+	if len(rest) > 0 {
+		style = strings.Join(rest, " ")
+	}
+
+	if _, err := bible.ParseStyle(style); err != nil {
+		return fmt.Errorf("highlight style: %w", err)
+	}
+
+	ids, err := s.annotate(state.KIND_HIGHLIGHT, passages, style)
+	printAdded(state.KIND_HIGHLIGHT, ids, passages)
+
+	return err
+}
+
+func highlightList(s *session, args []string) error {
+	rows, err := s.annotationsOf(state.KIND_HIGHLIGHT, nil)
+	if err != nil {
+		return err
+	}
+
+	if len(rows) < 1 {
+		return errors.New("no highlights found")
+	}
+
+	return s.printAnnotations(rows, "Style")
+}
+
+func highlightRemove(s *session, args []string) error {
+	passages, err := s.resolve(args)
+	if err != nil {
+		return err
+	}
+
+	n, err := s.removeAnnotations(state.KIND_HIGHLIGHT, passages, nil)
+	if err == nil && n < 1 {
+		err = fmt.Errorf("nothing is highlighted in %s", bible.NewPassageSet(passages...))
+	}
+
+	return err
+}
+
+func bookmarkAdd(s *session, args []string) error {
+	passages, rest, err := s.splitReference(args)
+	if err != nil {
+		return err
+	}
+
+	if len(rest) > 0 {
+		return fmt.Errorf("`%s` is not a reference", strings.Join(args, " "))
+	}
+
+	ids, err := s.annotate(state.KIND_BOOKMARK, passages, "")
+	printAdded(state.KIND_BOOKMARK, ids, passages)
+
+	return err
+}
+
+func bookmarkList(s *session, args []string) error {
+	rows, err := s.annotationsOf(state.KIND_BOOKMARK, nil)
+	if err != nil {
+		return err
+	}
+
+	if len(rows) < 1 {
+		return errors.New("no bookmarks found")
+	}
+
+	return s.printAnnotations(rows, "")
+}
+
+func bookmarkRemove(s *session, args []string) error {
+	passages, err := s.resolve(args)
+	if err != nil {
+		return err
+	}
+
+	n, err := s.removeAnnotations(state.KIND_BOOKMARK, passages, nil)
+	if err == nil && n < 1 {
+		err = fmt.Errorf("nothing is bookmarked in %s", bible.NewPassageSet(passages...))
+	}
+
+	return err
+}
@@ -0,0 +1,486 @@
+package main
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/ButbkaDrug/bible"
+	"github.com/ButbkaDrug/bible/internal/config"
+	"github.com/ButbkaDrug/bible/internal/plan"
+	"github.com/ButbkaDrug/bible/internal/state"
+)
+
+var planCommands []command
+
+func init() {
+	planCommands = []command{
+		{"list", "", "list plans and how far you are in them", planList},
+		{"start", "<plan> [date]", "start a plan today or on the date", planStart},
+		{"today", "[plan]", "read today's readings. This is the default", planToday},
+		{"done", "[plan] [day]", "mark today's readings or the day as read", planDone},
+		{"catchup", "[plan]", "move the plan so the first unread day is today", planCatchUp},
+		{"pause", "[plan]", "stop the plan from moving on", planPause},
+		{"resume", "[plan]", "go on with a paused plan", planResume},
+		{"reset", "[plan]", "start the plan over today", planReset},
+		{"stop", "[plan]", "forget the plan and its progress", planStop},
+	}
+}
+
+// `bible plan` runs the subcommand named by the first argument
+func runPlan(s *session, args []string) error {
+	return runSubcommand(s, "plan", planCommands, "today", args)
+}
+
+// runs one of the commands named by the first argument, or the default
+// one when there are no arguments
+func runSubcommand(s *session, parent string, commands []command, def string, args []string) error {
+	name := def
+	if len(args) > 0 {
+		name, args = args[0], args[1:]
+	}
+
+	i := slices.IndexFunc(commands, func(c command) bool { return c.name == name })
+	if i < 0 {
+		return fmt.Errorf("unknown %s command `%s`, expected %s", parent, name, strings.Join(subcommandNames(commands), ", "))
+	}
+
+	return commands[i].run(s, args)
+}
+
+func subcommandNames(commands []command) []string {
+	var names []string
+	for _, c := range commands {
+		names = append(names, c.name)
+	}
+
+	return names
+}
+
+// user plans from the plans directory next to the config, then the
+// built-in ones
+func allPlans() ([]plan.Plan, error) {
+	plans, err := plan.Load(filepath.Join(config.Dir(), "plans"))
+	if err != nil {
+		return nil, err
+	}
+
+	return append(plans, plan.Builtin()...), nil
+}
+
+func findPlan(name string) (plan.Plan, error) {
+	plans, err := allPlans()
+	if err != nil {
+		return plan.Plan{}, err
+	}
+
+	p, ok := plan.Find(plans, name)
+	if !ok {
+		return p, fmt.Errorf("unknown plan `%s`, see `bible plan list`", name)
+	}
+
+	return p, nil
+}
+
+// started plan named by the first argument, or the only one started.
+// Returns the arguments after the name
+func (s *session) startedPlan(args []string) (plan.Plan, plan.Progress, []string, error) {
+	store, err := s.state()
+	if err != nil {
+		return plan.Plan{}, plan.Progress{}, nil, err
+	}
+
+	var name string
+
+	if len(args) > 0 {
+		if _, err := strconv.Atoi(args[0]); err != nil {
+			name, args = args[0], args[1:]
+		}
+	}
+
+	if name == "" {
+		started, err := store.ListPlans(s.ctx)
+		if err != nil {
+			return plan.Plan{}, plan.Progress{}, nil, err
+		}
+
+		switch len(started) {
+		case 0:
+			return plan.Plan{}, plan.Progress{}, nil, errors.New("no plan is started, see `bible plan list`")
+		case 1:
+			name = started[0].Name
+		default:
+			var names []string
+			for _, p := range started {
+				names = append(names, p.Name)
+			}
+			return plan.Plan{}, plan.Progress{}, nil, fmt.Errorf("several plans are started, name one: %s", strings.Join(names, ", "))
+		}
+	}
+
+	p, err := findPlan(name)
+	if err != nil {
+		return p, plan.Progress{}, nil, err
+	}
+
+	progress, err := s.progress(p.Name)
+	if errors.Is(err, sql.ErrNoRows) {
+		err = fmt.Errorf("plan %s is not started, run `bible plan start %s`", p.Name, p.Name)
+	}
+
+	return p, progress, args, err
+}
+
+func (s *session) progress(name string) (plan.Progress, error) {
+	store, err := s.state()
+	if err != nil {
+		return plan.Progress{}, err
+	}
+
+	row, err := store.GetPlan(s.ctx, name)
+	if err != nil {
+		return plan.Progress{}, err
+	}
+
+	var progress = plan.Progress{PausedDays: int(row.PausedDays)}
+
+	if progress.Start, err = time.Parse(time.DateOnly, row.Start); err != nil {
+		return progress, fmt.Errorf("plan %s: %w", name, err)
+	}
+
+	if row.PausedAt != "" {
+		if progress.PausedAt, err = time.Parse(time.DateOnly, row.PausedAt); err != nil {
+			return progress, fmt.Errorf("plan %s: %w", name, err)
+		}
+	}
+
+	days, err := store.GetPlanDays(s.ctx, name)
+	if err != nil {
+		return progress, err
+	}
+
+	for _, d := range days {
+		progress.Done = append(progress.Done, int(d.Day))
+	}
+
+	return progress, nil
+}
+
+func (s *session) saveProgress(name string, progress plan.Progress) error {
+	store, err := s.state()
+	if err != nil {
+		return err
+	}
+
+	var pausedAt string
+	if progress.Paused() {
+		pausedAt = progress.PausedAt.Format(time.DateOnly)
+	}
+
+	return store.UpdatePlan(s.ctx, state.UpdatePlanParams{
+		Name:       name,
+		Start:      progress.Start.Format(time.DateOnly),
+		PausedAt:   pausedAt,
+		PausedDays: int64(progress.PausedDays),
+	})
+}
+
+func planList(s *session, args []string) error {
+	plans, err := allPlans()
+	if err != nil {
+		return err
+	}
+
+	tw := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintf(tw, "Plan\tTitle\tDays\tProgress\n")
+
+	for _, p := range plans {
+		status, err := s.planStatus(p)
+		if err != nil {
+			return err
+		}
+
+		fmt.Fprintf(tw, "%s\t%s\t%d\t%s\n", p.Name, p.Title, p.Len(), status)
+	}
+
+	return tw.Flush()
+}
+
+// how far the reader is in the plan, empty when it is not started
+func (s *session) planStatus(p plan.Plan) (string, error) {
+	progress, err := s.progress(p.Name)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+
+	today := time.Now()
+
+	var parts = []string{fmt.Sprintf("day %d, %d read", progress.Day(today), len(progress.Done))}
+
+	if behind := progress.Behind(today, p); len(behind) > 0 {
+		parts = append(parts, fmt.Sprintf("%d behind", len(behind)))
+	}
+
+	if progress.Finished(p) {
+		parts = []string{"finished"}
+	}
+
+	if progress.Paused() {
+		parts = append(parts, "paused")
+	}
+
+	return strings.Join(parts, ", "), nil
+}
+
+func planStart(s *session, args []string) error {
+	if len(args) < 1 {
+		return errors.New("name a plan to start, see `bible plan list`")
+	}
+
+	p, err := findPlan(args[0])
+	if err != nil {
+		return err
+	}
+
+	start := time.Now()
+	if len(args) > 1 {
+		if start, err = time.ParseInLocation(time.DateOnly, args[1], time.Local); err != nil {
+			return fmt.Errorf("start date must look like 2006-01-02: %w", err)
+		}
+	}
+
+	if _, err := s.progress(p.Name); err == nil {
+		return fmt.Errorf("plan %s is already started, `bible plan reset %s` starts it over", p.Name, p.Name)
+	}
+
+	return s.restartPlan(p, start)
+}
+
+func (s *session) restartPlan(p plan.Plan, start time.Time) error {
+	store, err := s.state()
+	if err != nil {
+		return err
+	}
+
+	if err := store.ClearPlanDays(s.ctx, p.Name); err != nil {
+		return err
+	}
+
+	err = store.StartPlan(s.ctx, state.StartPlanParams{
+		Name:  p.Name,
+		Start: start.Format(time.DateOnly),
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%s starts on %s\n", p.Title, start.Format(time.DateOnly))
+
+	return nil
+}
+
+// reads the day's passages the same way `bible <reference>` does
+func planToday(s *session, args []string) error {
+	p, progress, _, err := s.startedPlan(args)
+	if err != nil {
+		return err
+	}
+
+	today := time.Now()
+	day := progress.Day(today)
+
+	readings := p.Readings(day)
+	if len(readings) < 1 {
+		return fmt.Errorf("%s has %d days and it is day %d, `bible plan reset %s` starts it over", p.Title, p.Len(), day, p.Name)
+	}
+
+	if behind := progress.Behind(today, p); len(behind) > 0 {
+		fmt.Fprintf(os.Stderr, "%d days behind, `bible plan catchup` moves the plan to today\n", len(behind))
+	}
+
+	app, err := s.bible()
+	if err != nil {
+		return err
+	}
+
+	if s.cfg.Format != "json" {
+		header := fmt.Sprintf("%s, day %d of %d: %s", p.Title, day, p.Len(), strings.Join(readings, "; "))
+		if progress.IsDone(day) {
+			header += " (read)"
+		}
+		if progress.Paused() {
+			header += " (paused)"
+		}
+		fmt.Printf("%s\n\n", header)
+	}
+
+	var printed bool
+
+	for _, r := range readings {
+		verses, err := app.SetQuery(r).Execute()
+
+		// plans are written for the whole Bible, translations may not
+		// have every book
+		var notFound *bible.NotFoundError
+		if errors.As(err, &notFound) || err == nil && len(verses) < 1 {
+			fmt.Fprintf(os.Stderr, "%s is not in %s\n", r, s.cfg.Translation)
+			continue
+		}
+
+		if err != nil {
+			return fmt.Errorf("%s: %w", r, err)
+		}
+
+		if printed {
+			fmt.Println()
+		}
+		printed = true
+
+		if err := app.Render(verses); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func planDone(s *session, args []string) error {
+	p, progress, args, err := s.startedPlan(args)
+	if err != nil {
+		return err
+	}
+
+	day := progress.Day(time.Now())
+
+	if len(args) > 0 {
+		if day, err = strconv.Atoi(args[0]); err != nil {
+			return fmt.Errorf("day must be a number: %w", err)
+		}
+	}
+
+	if len(p.Readings(day)) < 1 {
+		return fmt.Errorf("%s has no day %d", p.Title, day)
+	}
+
+	store, err := s.state()
+	if err != nil {
+		return err
+	}
+
+	err = store.MarkPlanDay(s.ctx, state.MarkPlanDayParams{
+		Plan:   p.Name,
+		Day:    int64(day),
+		DoneAt: time.Now().Format(time.DateOnly),
+	})
+	if err != nil {
+		return err
+	}
+
+	if !progress.IsDone(day) {
+		progress.Done = append(progress.Done, day)
+	}
+
+	if progress.Finished(p) {
+		fmt.Printf("%s: day %d read, the plan is finished\n", p.Title, day)
+		return nil
+	}
+
+	fmt.Printf("%s: day %d read\n", p.Title, day)
+
+	return nil
+}
+
+func planCatchUp(s *session, args []string) error {
+	p, progress, _, err := s.startedPlan(args)
+	if err != nil {
+		return err
+	}
+
+	today := time.Now()
+	progress.CatchUp(today, p)
+
+	if err := s.saveProgress(p.Name, progress); err != nil {
+		return err
+	}
+
+	fmt.Printf("%s: today is day %d\n", p.Title, progress.Day(today))
+
+	return nil
+}
+
+func planPause(s *session, args []string) error {
+	p, progress, _, err := s.startedPlan(args)
+	if err != nil {
+		return err
+	}
+
+	progress.Pause(time.Now())
+
+	if err := s.saveProgress(p.Name, progress); err != nil {
+		return err
+	}
+
+	fmt.Printf("%s is paused on day %d\n", p.Title, progress.Day(time.Now()))
+
+	return nil
+}
+
+func planResume(s *session, args []string) error {
+	p, progress, _, err := s.startedPlan(args)
+	if err != nil {
+		return err
+	}
+
+	progress.Resume(time.Now())
+
+	if err := s.saveProgress(p.Name, progress); err != nil {
+		return err
+	}
+
+	fmt.Printf("%s: today is day %d\n", p.Title, progress.Day(time.Now()))
+
+	return nil
+}
+
+func planReset(s *session, args []string) error {
+	p, _, _, err := s.startedPlan(args)
+	if err != nil {
+		return err
+	}
+
+	return s.restartPlan(p, time.Now())
+}
+
+func planStop(s *session, args []string) error {
+	p, _, _, err := s.startedPlan(args)
+	if err != nil {
+		return err
+	}
+
+	store, err := s.state()
+	if err != nil {
+		return err
+	}
+
+	if err := store.ClearPlanDays(s.ctx, p.Name); err != nil {
+		return err
+	}
+
+	if err := store.DeletePlan(s.ctx, p.Name); err != nil {
+		return err
+	}
+
+	fmt.Printf("%s is stopped\n", p.Title)
+
+	return nil
+}
@@ -0,0 +1,119 @@
+package main
+
+import (
+	"errors"
+	"math/rand/v2"
+	"strings"
+	"time"
+
+	"github.com/ButbkaDrug/bible"
+)
+
+func runRandom(s *session, args []string) error {
+	app, err := s.bible()
+	if err != nil {
+		return err
+	}
+
+	scope, err := randomScope(app, args)
+	if err != nil {
+		return err
+	}
+
+	r := rand.New(rand.NewPCG(rand.Uint64(), rand.Uint64()))
+
+	verses, err := app.PickRandom(r, scope, s.pick())
+	if err != nil {
+		return err
+	}
+
+	return renderPicked(app, verses)
+}
+
+// verse of the day depends only on the date, the seed and the scope,
+// so everyone with the same module and config gets the same verse
+func runVotd(s *session, args []string) error {
+	date := time.Now()
+
+	if len(args) > 0 {
+		if d, err := time.ParseInLocation(time.DateOnly, args[0], time.Local); err == nil {
+			date = d
+			args = args[1:]
+		}
+	}
+
+	app, err := s.bible()
+	if err != nil {
+		return err
+	}
+
+	scope, err := randomScope(app, args)
+	if err != nil {
+		return err
+	}
+
+	verses, err := app.VerseOfTheDay(date, s.cfg.Seed, scope, s.pick())
+	if err != nil {
+		return err
+	}
+
+	return renderPicked(app, verses)
+}
+
+func renderPicked(app *bible.Bible, verses []bible.Verse) error {
+	verses, err := app.AddContext(verses)
+	if err != nil {
+		return err
+	}
+
+	return app.Render(verses)
+}
+
+func (s *session) pick() bible.Pick {
+	if s.passage {
+		return bible.PICK_PASSAGE
+	}
+
+	return bible.PICK_VERSE
+}
+
+// testaments and genres pick from their books, anything else is read
+// as a list of references to pick from
+func randomScope(app *bible.Bible, args []string) (bible.RandomScope, error) {
+	if len(args) < 1 {
+		return bible.RandomScope{}, nil
+	}
+
+	var filters []func(bible.Book) bool
+	for _, arg := range args {
+		f, err := bible.ParseBookFilter(arg)
+		if err != nil {
+			passages, err := app.Resolve(strings.Join(args, " "))
+			return bible.RandomScope{Passages: passages}, err
+		}
+		filters = append(filters, f)
+	}
+
+	books, err := app.Books()
+	if err != nil {
+		return bible.RandomScope{}, err
+	}
+
+	var scope bible.RandomScope
+
+books:
+	for _, b := range books {
+		for _, f := range filters {
+			if !f(b) {
+				continue books
+			}
+		}
+		scope.Books = append(scope.Books, b.Number)
+	}
+
+	if len(scope.Books) < 1 {
+		return scope, errors.New("no books found")
+	}
+
+	return scope, nil
+}
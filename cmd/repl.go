@@ -0,0 +1,369 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/ButbkaDrug/bible"
+	"github.com/ButbkaDrug/bible/internal/config"
+	"golang.org/x/term"
+)
+
+const (
+	HISTORY_FILE = "repl_history"
+	HISTORY_SIZE = 1000
+)
+
+const replHelp = `Type a reference or words to search for. Other commands:
+  +N, -N    verse N verses after or before the last one shown
+  next      next chapter
+  prev      previous chapter
+  ctx N     last passage with N verses around it
+  :t NAME   switch translation
+  :history  show history
+  :q        quit (Ctrl-D works too)
+`
+
+var relativeVerse = regexp.MustCompile(`^[+-]\d+$`)
+
+type lineReader interface {
+	ReadLine() (string, error)
+}
+
+type scannerReader struct {
+	scanner *bufio.Scanner
+}
+
+func (r scannerReader) ReadLine() (string, error) {
+	if !r.scanner.Scan() {
+		if err := r.scanner.Err(); err != nil {
+			return "", err
+		}
+		return "", io.EOF
+	}
+
+	return r.scanner.Text(), nil
+}
+
+// keeps the history of the session in a file, so it survives restarts
+type fileHistory struct {
+	entries []string
+	path    string
+}
+
+func loadHistory(path string) *fileHistory {
+	h := &fileHistory{path: path}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return h
+	}
+
+	for _, line := range strings.Split(string(data), "\n") {
+		if line != "" {
+			h.entries = append(h.entries, line)
+		}
+	}
+
+	if len(h.entries) > HISTORY_SIZE {
+		h.entries = h.entries[len(h.entries)-HISTORY_SIZE:]
+	}
+
+	return h
+}
+
+func (h *fileHistory) Add(entry string) {
+	if entry == "" || (len(h.entries) > 0 && h.entries[len(h.entries)-1] == entry) {
+		return
+	}
+
+	h.entries = append(h.entries, entry)
+
+	if err := os.MkdirAll(filepath.Dir(h.path), 0o755); err != nil {
+		return
+	}
+
+	f, err := os.OpenFile(h.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return
+	}
+	defer f.Close()
+
+	fmt.Fprintln(f, entry)
+}
+
+func (h *fileHistory) Len() int {
+	return len(h.entries)
+}
+
+func (h *fileHistory) At(i int) string {
+	return h.entries[len(h.entries)-1-i]
+}
+
+type repl struct {
+	s   *session
+	app *bible.Bible
+	nav *navigator
+	out io.Writer
+
+	history *fileHistory
+	// what was printed last and what was asked for last. `+1` moves
+	// from the first and `ctx` extends the second
+	shown  []bible.Verse
+	passed []bible.Verse
+}
+
+// keeps the module open and reads one query per line
+func runREPL(s *session, args []string) error {
+	s.untimed()
+
+	r := &repl{
+		s:       s,
+		out:     os.Stdout,
+		history: loadHistory(filepath.Join(config.StateDir(), HISTORY_FILE)),
+	}
+
+	var input lineReader = scannerReader{bufio.NewScanner(os.Stdin)}
+
+	fd := int(os.Stdin.Fd())
+
+	if term.IsTerminal(fd) && term.IsTerminal(int(os.Stdout.Fd())) {
+		if width, _, err := term.GetSize(int(os.Stdout.Fd())); err == nil && s.cfg.Width == 0 {
+			s.cfg.Width = width
+		}
+
+		state, err := term.MakeRaw(fd)
+		if err != nil {
+			return err
+		}
+		defer term.Restore(fd, state)
+
+		t := term.NewTerminal(struct {
+			io.Reader
+			io.Writer
+		}{os.Stdin, os.Stdout}, "")
+		t.History = r.history
+
+		input = t
+		r.out = t
+	}
+
+	if err := r.open(); err != nil {
+		return err
+	}
+
+	if len(args) > 0 {
+		r.eval(strings.Join(args, " "))
+	}
+
+	for {
+		if t, ok := input.(*term.Terminal); ok {
+			t.SetPrompt(r.s.cfg.Translation + "> ")
+		}
+
+		line, err := input.ReadLine()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		if _, ok := input.(*term.Terminal); !ok {
+			r.history.Add(strings.TrimSpace(line))
+		}
+
+		if !r.eval(line) {
+			return nil
+		}
+	}
+}
+
+func (r *repl) open() error {
+	app, err := r.s.bible()
+	if err != nil {
+		return err
+	}
+
+	nav, err := newNavigator(app)
+	if err != nil {
+		return err
+	}
+
+	r.app = app.SetWriter(r.out)
+	r.nav = nav
+
+	return nil
+}
+
+// runs a line of input. Returns false when it is time to quit
+func (r *repl) eval(line string) bool {
+	line = strings.TrimSpace(line)
+
+	var err error
+
+	switch {
+	case line == "":
+	case line == ":q" || line == "quit" || line == "exit":
+		return false
+	case line == ":h" || line == "help" || line == "?":
+		fmt.Fprint(r.out, replHelp)
+	case line == ":history":
+		for i := r.history.Len() - 1; i >= 0; i-- {
+			fmt.Fprintln(r.out, r.history.At(i))
+		}
+	case strings.HasPrefix(line, ":t"):
+		err = r.translation(strings.TrimSpace(strings.TrimPrefix(line, ":t")))
+	case line == "next":
+		err = r.chapter(1)
+	case line == "prev":
+		err = r.chapter(-1)
+	case relativeVerse.MatchString(line):
+		n, _ := strconv.Atoi(line)
+		err = r.step(n)
+	case strings.HasPrefix(line, "ctx ") || line == "ctx":
+		err = r.context(strings.TrimSpace(strings.TrimPrefix(line, "ctx")))
+	default:
+		err = r.lookup(line)
+	}
+
+	if err != nil {
+		fmt.Fprintf(r.out, "error: %s\n", err)
+		printCaret(r.out, err)
+	}
+
+	return true
+}
+
+func (r *repl) show(verses []bible.Verse) error {
+	if len(verses) < 1 {
+		return errors.New("nothing found")
+	}
+
+	r.shown = verses
+
+	return r.app.Render(verses)
+}
+
+func (r *repl) lookup(query string) error {
+	r.app.SetHighlights([]string{})
+
+	verses, err := r.app.SetQuery(query).Execute()
+	if err != nil {
+		return err
+	}
+
+	// list of books has no place to move from
+	if len(verses) > 0 && verses[0].Chapter > 0 {
+		r.passed = verses
+	}
+
+	return r.show(verses)
+}
+
+func (r *repl) last() (bible.Verse, error) {
+	if len(r.shown) < 1 || r.shown[0].Chapter < 1 {
+		return bible.Verse{}, errors.New("look something up first")
+	}
+
+	return r.shown[len(r.shown)-1], nil
+}
+
+func (r *repl) step(n int) error {
+	at, err := r.last()
+	if err != nil {
+		return err
+	}
+
+	if n < 0 {
+		at = r.shown[0]
+	}
+
+	v, err := r.app.Offset(at, n)
+	if err != nil {
+		return err
+	}
+
+	r.passed = []bible.Verse{v}
+
+	return r.show(r.passed)
+}
+
+func (r *repl) chapter(direction int) error {
+	at, err := r.last()
+	if err != nil {
+		return err
+	}
+
+	if direction < 0 {
+		at, err = r.app.PrevChapter(r.shown[0])
+	} else {
+		at, err = r.app.NextChapter(at)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	verses, err := r.nav.chapter(at.BookNumber, at.Chapter)
+	if err != nil {
+		return err
+	}
+
+	r.passed = verses
+
+	return r.show(verses)
+}
+
+func (r *repl) context(arg string) error {
+	n, err := strconv.Atoi(arg)
+	if err != nil || n < 0 {
+		return errors.New("usage: ctx N")
+	}
+
+	if len(r.passed) < 1 {
+		return errors.New("look something up first")
+	}
+
+	first, last := r.passed[0], r.passed[len(r.passed)-1]
+
+	from, err := r.app.Offset(first, -n)
+	if err != nil {
+		from = first
+	}
+
+	to, err := r.app.Offset(last, n)
+	if err != nil {
+		to = last
+	}
+
+	verses, err := r.app.Between(from, to)
+	if err != nil {
+		return err
+	}
+
+	return r.show(verses)
+}
+
+func (r *repl) translation(name string) error {
+	if name == "" {
+		fmt.Fprintln(r.out, r.s.cfg.Translation)
+		return nil
+	}
+
+	if err := r.s.reopen(name); err != nil {
+		return err
+	}
+
+	r.shown = nil
+	r.passed = nil
+
+	return r.open()
+}
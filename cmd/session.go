@@ -0,0 +1,237 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/ButbkaDrug/bible"
+	"github.com/ButbkaDrug/bible/internal/config"
+	"github.com/ButbkaDrug/bible/internal/state"
+	_ "modernc.org/sqlite"
+)
+
+// session holds everything a command needs. Database is opened on the
+// first use, so commands like `config` work without a module.
+type session struct {
+	cfg   config.Config
+	limit int
+	all   bool
+	// random and votd pick passages
+	passage bool
+	// translation was picked with a flag
+	pickedTranslation bool
+	before            int
+	after             int
+	ctx               context.Context
+	cancel            context.CancelFunc
+
+	conn *sql.DB
+	app  *bible.Bible
+
+	// per-user database with reading progress, annotations and history
+	stateConn *sql.DB
+	store     *state.Queries
+}
+
+func newSession(o options) (*session, error) {
+	cfg, err := config.Load(config.Path())
+	if err != nil {
+		return nil, err
+	}
+
+	if err := cfg.ApplyEnv(os.Getenv); err != nil {
+		return nil, err
+	}
+
+	if err := o.apply(&cfg); err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.Timeout.Duration)
+	before, after := o.contextVerses()
+
+	return &session{
+		cfg:     cfg,
+		limit:   o.limit,
+		all:     o.all,
+		passage: o.passage,
+		before:  before,
+		after:   after,
+		ctx:     ctx,
+		cancel:  cancel,
+
+		pickedTranslation: o.set["t"] || o.set["translation"],
+	}, nil
+}
+
+func (s *session) bible() (*bible.Bible, error) {
+	if s.app != nil {
+		return s.app, nil
+	}
+
+	DATABASE, err := s.cfg.DatabasePath()
+	if err != nil {
+		return nil, err
+	}
+
+	render, err := newRender(s.cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	names, err := s.cfg.BookNamesByNumber()
+	if err != nil {
+		return nil, err
+	}
+
+	canon, err := s.cfg.CanonEntries()
+	if err != nil {
+		return nil, err
+	}
+
+	s.conn, err = sql.Open("sqlite", DATABASE)
+	if err != nil {
+		return nil, fmt.Errorf("database connection error: %w", err)
+	}
+
+	s.app = bible.New(s.ctx, s.conn, os.Getenv("BIBLE_ENV")).
+		SetRender(render).
+		SetName(s.cfg.Translation).
+		SetHeadings(s.cfg.Headings).
+		SetAliases(s.cfg.Aliases).
+		AddAliasTable(names).
+		SetLimit(s.limit).
+		SetWholeBooks(s.all).
+		SetContextVerses(s.before, s.after)
+
+	if canon != nil {
+		c, err := s.app.NewCanon(s.cfg.Canon, canon)
+		if err != nil {
+			return nil, err
+		}
+		s.app.SetCanon(c)
+	}
+
+	if s.cfg.History {
+		s.app.SetOnLookup(s.recordLookup)
+	}
+
+	if s.cfg.Annotations {
+		store, err := s.state()
+		if err != nil {
+			return nil, err
+		}
+		s.app.SetAnnotations(annotations{store})
+	}
+
+	return s.app, nil
+}
+
+// switches the session to another translation. Current one stays open
+// when the new one can't be found
+func (s *session) reopen(translation string) error {
+	cfg := s.cfg
+	cfg.Translation = translation
+
+	if _, err := cfg.DatabasePath(); err != nil {
+		return err
+	}
+
+	if s.conn != nil {
+		s.conn.Close()
+	}
+
+	s.cfg = cfg
+	s.conn = nil
+	s.app = nil
+
+	_, err := s.bible()
+
+	return err
+}
+
+// opens the state database of the user on the first use
+func (s *session) state() (*state.Queries, error) {
+	if s.store != nil {
+		return s.store, nil
+	}
+
+	conn, err := state.Open(s.ctx, filepath.Join(config.StateDir(), state.STATE_FILE))
+	if err != nil {
+		return nil, err
+	}
+
+	s.stateConn = conn
+	s.store = state.New(conn)
+
+	return s.store, nil
+}
+
+func (s *session) close() {
+	if s.conn != nil {
+		s.conn.Close()
+	}
+	if s.stateConn != nil {
+		s.stateConn.Close()
+	}
+	s.cancel()
+}
+
+func newRender(cfg config.Config) (bible.Renderer, error) {
+	switch cfg.Format {
+	case "json":
+		return bible.NewJSONRender(), nil
+	case "text", "":
+	default:
+		return nil, fmt.Errorf("unknown output format `%s`", cfg.Format)
+	}
+
+	mode, err := colorMode(cfg.Color)
+	if err != nil {
+		return nil, err
+	}
+
+	theme, err := loadTheme(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	render := bible.NewDefaultRender().
+		SetTheme(theme).
+		SetColorMode(mode).
+		SetWidth(cfg.Width).
+		ShowFootnotes(cfg.Footnotes)
+
+	switch cfg.Layout {
+	case "verse":
+		render.SetLayout(bible.VERSE_PER_LINE)
+	case "paragraph", "":
+		render.SetLayout(bible.PARAGRAPH)
+	default:
+		return nil, fmt.Errorf("unknown layout `%s`", cfg.Layout)
+	}
+
+	return render, nil
+}
+
+func loadTheme(cfg config.Config) (bible.Theme, error) {
+	theme, err := bible.LoadTheme(filepath.Join(config.Dir(), "themes"), cfg.Theme)
+	if err != nil {
+		return theme, fmt.Errorf("failed to load theme: %w", err)
+	}
+
+	return theme, nil
+}
+
+// NO_COLOR wins over everything but the explicit choice of the user,
+// so it is only looked at when the mode is detected
+func colorMode(s string) (bible.ColorMode, error) {
+	if s == "auto" || s == "" {
+		return bible.DetectColorMode(os.Stdout), nil
+	}
+
+	return bible.ParseColorMode(s)
+}
@@ -0,0 +1,490 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/ButbkaDrug/bible"
+	"golang.org/x/term"
+)
+
+const (
+	altScreenOn  = "\033[?1049h\033[?25l"
+	altScreenOff = "\033[?25h\033[?1049l"
+	clearScreen  = "\033[H\033[2J"
+	resetStyle   = "\033[0m"
+)
+
+type verseRenderer interface {
+	bible.Renderer
+	RenderVerse(bible.Verse, int) string
+}
+
+type location struct {
+	book    int
+	chapter int
+	verse   int
+}
+
+func (l location) ref() bible.Verse {
+	return bible.Verse{BookNumber: l.book, Chapter: l.chapter, Verse: l.verse}
+}
+
+// full screen reader. Shows one chapter or one page of search results
+// at a time.
+type reader struct {
+	app    *bible.Bible
+	nav    *navigator
+	render verseRenderer
+	mode   bible.ColorMode
+	theme  bible.Theme
+
+	in  *os.File
+	out io.Writer
+
+	width  int
+	height int
+
+	// what is on the screen
+	at     location
+	search string
+	title  string
+	lines  []string
+	links  []location
+	offset int
+
+	message string
+}
+
+func runTUI(s *session, args []string) error {
+	if !term.IsTerminal(int(os.Stdin.Fd())) || !term.IsTerminal(int(os.Stdout.Fd())) {
+		return errors.New("tui needs a terminal")
+	}
+
+	// reader can stay open for hours, so no timeout
+	s.untimed()
+
+	app, err := s.bible()
+	if err != nil {
+		return err
+	}
+
+	nav, err := newNavigator(app)
+	if err != nil {
+		return err
+	}
+
+	mode, err := colorMode(s.cfg.Color)
+	if err != nil {
+		return err
+	}
+
+	theme, err := loadTheme(s.cfg)
+	if err != nil {
+		return err
+	}
+
+	r := &reader{
+		app:    app,
+		nav:    nav,
+		mode:   mode,
+		theme:  theme,
+		render: bible.NewDefaultRender().SetTheme(theme).SetColorMode(mode),
+		in:     os.Stdin,
+		out:    os.Stdout,
+	}
+
+	start := location{book: int(nav.books[0].BookNumber), chapter: 1}
+
+	if len(args) > 0 {
+		start, err = r.resolve(strings.Join(args, " "))
+		if err != nil {
+			return err
+		}
+	}
+
+	state, err := term.MakeRaw(int(r.in.Fd()))
+	if err != nil {
+		return err
+	}
+	defer term.Restore(int(r.in.Fd()), state)
+
+	fmt.Fprint(r.out, altScreenOn)
+	defer fmt.Fprint(r.out, altScreenOff)
+
+	r.resize()
+	if err := r.open(start); err != nil {
+		return err
+	}
+
+	return r.loop()
+}
+
+// uses the same query parser as the command line. When the query is
+// a reference the first verse it returns is where the reader goes
+func (r *reader) resolve(query string) (location, error) {
+	verses, err := r.app.SetQuery(query).Execute()
+	if err != nil {
+		return location{}, err
+	}
+
+	if len(verses) < 1 || verses[0].Chapter < 1 {
+		return location{}, fmt.Errorf("`%s` not found", query)
+	}
+
+	v := verses[0]
+
+	return location{book: v.BookNumber, chapter: v.Chapter, verse: v.Verse}, nil
+}
+
+func (r *reader) resize() bool {
+	width, height, err := term.GetSize(int(os.Stdout.Fd()))
+	if err != nil {
+		width, height = 80, 24
+	}
+
+	changed := width != r.width || height != r.height
+	r.width, r.height = width, height
+
+	return changed
+}
+
+// opens the chapter and scrolls to the verse
+func (r *reader) open(at location) error {
+	verses, err := r.nav.chapter(at.book, at.chapter)
+	if err != nil {
+		return err
+	}
+
+	if len(verses) < 1 {
+		return fmt.Errorf("%d:%d not found", at.book, at.chapter)
+	}
+
+	r.at = at
+	r.search = ""
+	r.title = fmt.Sprintf("%s %d", verses[0].Book, at.chapter)
+	r.lines = []string{r.styled(r.theme.Title, r.title), ""}
+	r.links = []location{{}, {}}
+	r.offset = 0
+
+	for _, v := range verses {
+		if v.Verse == at.verse {
+			r.offset = len(r.lines)
+		}
+
+		r.addVerse(v)
+	}
+
+	return nil
+}
+
+func (r *reader) openSearch(query string) error {
+	verses, err := r.app.Search(query)
+	if err != nil {
+		return err
+	}
+
+	if len(verses) < 1 {
+		return fmt.Errorf("nothing found for `%s`", query)
+	}
+
+	r.render.SetHighlights(strings.Fields(query))
+	defer r.render.SetHighlights([]string{})
+
+	r.search = query
+	r.title = fmt.Sprintf("/%s (%d)", query, len(verses))
+	r.lines = []string{}
+	r.links = []location{}
+	r.offset = 0
+
+	for _, v := range verses {
+		ref := fmt.Sprintf("%s %d:%d", v.Book, v.Chapter, v.Verse)
+
+		r.lines = append(r.lines, r.styled(r.theme.Title, ref))
+		r.links = append(r.links, location{v.BookNumber, v.Chapter, v.Verse})
+		r.addVerse(v)
+		r.lines = append(r.lines, "")
+		r.links = append(r.links, location{})
+	}
+
+	return nil
+}
+
+func (r *reader) addVerse(v bible.Verse) {
+	text := r.render.RenderVerse(v, r.width-1)
+
+	for _, line := range strings.Split(text, "\n") {
+		r.lines = append(r.lines, line)
+		r.links = append(r.links, location{v.BookNumber, v.Chapter, v.Verse})
+	}
+}
+
+func (r *reader) styled(s bible.Style, text string) string {
+	if seq := s.Sequence(r.mode); seq != "" {
+		return seq + text + resetStyle
+	}
+
+	return text
+}
+
+func (r *reader) nextChapter() error {
+	v, err := r.app.NextChapter(r.at.ref())
+	if err != nil {
+		return err
+	}
+
+	return r.open(location{book: v.BookNumber, chapter: v.Chapter})
+}
+
+func (r *reader) prevChapter() error {
+	v, err := r.app.PrevChapter(r.at.ref())
+	if err != nil {
+		return err
+	}
+
+	return r.open(location{book: v.BookNumber, chapter: v.Chapter})
+}
+
+func (r *reader) pageHeight() int {
+	if r.height < 2 {
+		return 1
+	}
+	return r.height - 1
+}
+
+func (r *reader) scroll(n int) {
+	r.offset += n
+
+	if last := len(r.lines) - r.pageHeight(); r.offset > last {
+		r.offset = last
+	}
+
+	if r.offset < 0 {
+		r.offset = 0
+	}
+}
+
+func (r *reader) statusBar() string {
+	var color bible.Color
+
+	if i := r.nav.bookIndex(r.at.book); i >= 0 && r.search == "" {
+		color, _ = bible.ParseColor(r.nav.books[i].BookColor)
+	}
+
+	black, _ := bible.ParseColor("black")
+	style := bible.Style{Fg: black, Bg: color}
+
+	if r.mode == bible.NO_COLOR || color == (bible.Color{}) {
+		style = bible.Style{}
+	}
+
+	left := " " + r.title
+	if r.message != "" {
+		left = " " + r.message
+	}
+
+	var position = 100
+	if last := len(r.lines) - r.pageHeight(); last > 0 {
+		position = r.offset * 100 / last
+	}
+
+	right := fmt.Sprintf("%d%%  n/p chapter  g go  / search  q quit ", position)
+
+	pad := r.width - utf8.RuneCountInString(left) - utf8.RuneCountInString(right)
+	if pad < 1 {
+		right = fmt.Sprintf("%d%% ", position)
+		pad = r.width - utf8.RuneCountInString(left) - utf8.RuneCountInString(right)
+	}
+
+	bar := left + strings.Repeat(" ", max(pad, 1)) + right
+
+	if seq := style.Sequence(r.mode); seq != "" {
+		return seq + bar + resetStyle
+	}
+
+	// reverse video works everywhere
+	return "\033[7m" + bar + resetStyle
+}
+
+func (r *reader) draw() {
+	var screen = new(strings.Builder)
+
+	screen.WriteString(clearScreen)
+
+	end := min(r.offset+r.pageHeight(), len(r.lines))
+
+	for _, line := range r.lines[r.offset:end] {
+		screen.WriteString(line)
+		screen.WriteString("\r\n")
+	}
+
+	for range r.pageHeight() - (end - r.offset) {
+		screen.WriteString("~\r\n")
+	}
+
+	screen.WriteString(r.statusBar())
+
+	fmt.Fprint(r.out, screen.String())
+}
+
+func (r *reader) readKey() (string, error) {
+	var buf = make([]byte, 32)
+
+	n, err := r.in.Read(buf)
+	if err != nil {
+		return "", err
+	}
+
+	return string(buf[:n]), nil
+}
+
+// reads a line on the status bar. Returns false when Esc was pressed
+func (r *reader) prompt(prefix string) (string, bool, error) {
+	var input string
+
+	for {
+		fmt.Fprintf(r.out, "\033[%d;1H\033[2K%s%s\033[?25h", r.height, prefix, input)
+
+		key, err := r.readKey()
+		if err != nil {
+			return "", false, err
+		}
+
+		switch key {
+		case "\r", "\n":
+			fmt.Fprint(r.out, "\033[?25l")
+			return strings.TrimSpace(input), true, nil
+		case "\033", "\x03":
+			fmt.Fprint(r.out, "\033[?25l")
+			return "", false, nil
+		case "\x7f", "\b":
+			if len(input) > 0 {
+				_, size := utf8.DecodeLastRuneInString(input)
+				input = input[:len(input)-size]
+			}
+		case "\x15":
+			input = ""
+		default:
+			if !strings.HasPrefix(key, "\033") && utf8.ValidString(key) && key[0] >= ' ' {
+				input += key
+			}
+		}
+	}
+}
+
+// follows the link of the line at the top of the screen. In a chapter
+// it just scrolls down
+func (r *reader) follow() error {
+	if r.search == "" {
+		r.scroll(1)
+		return nil
+	}
+
+	for _, l := range r.links[r.offset:] {
+		if l.book != 0 {
+			return r.open(l)
+		}
+	}
+
+	return nil
+}
+
+func (r *reader) loop() error {
+	for {
+		if r.resize() {
+			if err := r.reload(); err != nil {
+				return err
+			}
+		}
+
+		r.draw()
+		r.message = ""
+
+		key, err := r.readKey()
+		if err != nil {
+			return err
+		}
+
+		switch key {
+		case "q", "\x03":
+			return nil
+		case "\r":
+			err = r.follow()
+		case "j", "\033[B":
+			r.scroll(1)
+		case "k", "\033[A":
+			r.scroll(-1)
+		case " ", "f", "\033[6~":
+			r.scroll(r.pageHeight() - 1)
+		case "b", "\033[5~":
+			r.scroll(-(r.pageHeight() - 1))
+		case "\033[H", "<":
+			r.offset = 0
+		case "\033[F", ">", "G":
+			r.scroll(len(r.lines))
+		case "n", "\033[C":
+			err = r.nextChapter()
+		case "p", "\033[D":
+			err = r.prevChapter()
+		case "g":
+			err = r.goTo()
+		case "/":
+			err = r.find()
+		}
+
+		if err != nil {
+			r.message = err.Error()
+		}
+	}
+}
+
+func (r *reader) goTo() error {
+	query, ok, err := r.prompt("go to: ")
+	if err != nil || !ok || query == "" {
+		return err
+	}
+
+	at, err := r.resolve(query)
+	if err != nil {
+		return err
+	}
+
+	return r.open(at)
+}
+
+func (r *reader) find() error {
+	query, ok, err := r.prompt("/")
+	if err != nil || !ok || query == "" {
+		return err
+	}
+
+	return r.openSearch(query)
+}
+
+// rebuilds the page, lines depend on the width of the screen
+func (r *reader) reload() error {
+	if r.search != "" {
+		offset := r.offset
+		err := r.openSearch(r.search)
+		r.scroll(offset)
+		return err
+	}
+
+	at := r.at
+	if r.offset < len(r.links) {
+		at.verse = r.links[r.offset].verse
+	}
+
+	return r.open(at)
+}
+
+// interactive commands run for as long as the user wants
+func (s *session) untimed() {
+	s.cancel()
+	s.ctx, s.cancel = context.WithCancel(context.Background())
+}
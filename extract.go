@@ -0,0 +1,152 @@
+package bible
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// chapter and verse of one reference: `8:28`, `3.16`, `3:16b`, `3:16ff`
+const citationVerse = `\d+(?:[:.]\d+)?(?:ff|f|[abc])?`
+
+// chapter and verse part of a reference: `8:28`, `5:17-21`, `3:16, 18`
+var citationNumbers = regexp.MustCompile(
+	citationVerse + `(?:[ \t]*[-–—][ \t]*` + citationVerse + `)?` +
+		`(?:[ \t]*,[ \t]*` + citationVerse + `(?:[ \t]*[-–—][ \t]*` + citationVerse + `)?)*`,
+)
+
+// longest book name is `Song of Songs` plus a number, e.g. `1 Kings`
+const MAX_NAME_WORDS = 4
+
+// Citation is a reference found in free text
+type Citation struct {
+	// what the text says, e.g. `Rom. 8:28`
+	Text string
+	// byte offsets of the reference in the text, End is not included
+	Start int
+	End   int
+
+	Passages []Passage
+}
+
+// ExtractReferences finds every reference in the text. A reference is
+// a book name, abbreviation or alias that the module knows, followed by
+// chapter and verses in any form Parse understands. Book names have to
+// start with a capital letter or a number, so `am 3` in a sentence is
+// not taken for Amos.
+func (app *Bible) ExtractReferences(text string) []Citation {
+	var result []Citation
+
+	for _, m := range citationNumbers.FindAllStringIndex(text, -1) {
+		// `v12` or `1:2:3` are not references
+		if m[1] < len(text) {
+			if r, _ := utf8.DecodeRuneInString(text[m[1]:]); r == ':' || unicode.IsLetter(r) {
+				continue
+			}
+		}
+
+		start, name, ok := app.citationBook(text[:m[0]])
+		if !ok {
+			continue
+		}
+
+		numbers := strings.NewReplacer("–", "-", "—", "-").Replace(text[m[0]:m[1]])
+
+		passages, err := app.Resolve(name + " " + numbers)
+		if err != nil || len(passages) < 1 {
+			continue
+		}
+
+		result = append(result, Citation{
+			Text:     text[start:m[1]],
+			Start:    start,
+			End:      m[1],
+			Passages: passages,
+		})
+	}
+
+	return result
+}
+
+// will look for the book name right before the numbers. Returns where
+// the name starts and the name the way getBookNumber understands it
+func (app *Bible) citationBook(before string) (int, string, bool) {
+	var words []string
+	var starts []int
+
+	end := len(before)
+
+	// one space at most between the name and the numbers
+	if r, size := utf8.DecodeLastRuneInString(before); r == ' ' || r == '\t' {
+		end -= size
+	}
+
+	for len(words) < MAX_NAME_WORDS {
+		i := end
+		for i > 0 {
+			r, size := utf8.DecodeLastRuneInString(before[:i])
+			if !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '.' && !isApostrophe(r) {
+				break
+			}
+			i -= size
+		}
+
+		// `1-е` of `1-е Коринфянам`
+		if len(words) > 0 && isOrdinalSuffix(before[i:end]) &&
+			i >= 2 && before[i-1] == '-' && before[i-2] >= '1' && before[i-2] <= '4' {
+			i -= 2
+		}
+
+		word := before[i:end]
+		if word == "" || strings.Trim(word, ".") == "" {
+			break
+		}
+
+		// a word before the numbers can not be a number itself
+		if len(words) == 0 && !unicode.IsLetter(lastLetter(word)) {
+			break
+		}
+
+		words = append([]string{strings.TrimRight(word, ".")}, words...)
+		starts = append([]int{i}, starts...)
+
+		if i == 0 || before[i-1] != ' ' {
+			break
+		}
+		end = i - 1
+	}
+
+	for i := range words {
+		first, _ := utf8.DecodeRuneInString(words[i])
+		if !unicode.IsUpper(first) && !unicode.IsDigit(first) {
+			continue
+		}
+
+		// names are read the way the parser reads them
+		name, rest := parseName(normalizeOrdinals(strings.Join(words[i:], " ")))
+		if rest != "" {
+			continue
+		}
+
+		if _, ok := app.LookupBook(name); ok {
+			return starts[i], name, true
+		}
+	}
+
+	return 0, "", false
+}
+
+func lastLetter(s string) rune {
+	r, _ := utf8.DecodeLastRuneInString(strings.TrimRight(s, "."))
+	return r
+}
+
+// short lower case ending of a number, the `е` of `1-е`
+func isOrdinalSuffix(s string) bool {
+	if s == "" || utf8.RuneCountInString(s) > 2 {
+		return false
+	}
+
+	return strings.ToLower(s) == s
+}
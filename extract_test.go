@@ -0,0 +1,49 @@
+package bible
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestExtractReferences(t *testing.T) {
+	app := testModule(t)
+
+	tests := []string{
+		"as John says in Jn. 20:2 and again in Acts 1:1–3, 2",
+		"see John 21, not john 20:1 or Acts 1:1:2",
+		"Jn 20:3-21:1; Acts1:2",
+		"no references here, only 3:16 and Hezekiah 1:1",
+		"1 Acts 1:1",
+		"read I John 1:9 and 1-е Иоанна 1:9",
+		"см. Ин 20.2 и John 20:1ff. Jn 21,1b",
+	}
+
+	expectedResults := []string{
+		"[Jn. 20:2 (16-24) [John 20:2]] [Acts 1:1–3, 2 (38-53) [Acts 1:1-3 Acts 1:2]]",
+		"[John 21 (4-11) [John 21]]",
+		"[Jn 20:3-21:1 (0-12) [John 20:3-21:1]] [Acts1:2 (14-21) [Acts 1:2]]",
+		"",
+		"[Acts 1:1 (2-10) [Acts 1:1]]",
+		"[I John 1:9 (5-15) [1 John 1:9]] [1-е Иоанна 1:9 (20-41) [1 John 1:9]]",
+		"[Ин 20.2 (6-15) [John 20:2]] [John 20:1ff (19-30) [John 20:1-end]] [Jn 21,1b (32-40) [John 21:1]]",
+	}
+
+	for i, test := range tests {
+		var result string
+
+		for j, c := range app.ExtractReferences(test) {
+			if test[c.Start:c.End] != c.Text {
+				t.Fatalf("TEST[%d] failed: wrong offsets %d-%d for %s", i, c.Start, c.End, c.Text)
+			}
+
+			if j > 0 {
+				result += " "
+			}
+			result += fmt.Sprintf("[%s (%d-%d) %v]", c.Text, c.Start, c.End, c.Passages)
+		}
+
+		if result != expectedResults[i] {
+			t.Fatalf("TEST[%d] failed: expected %s got %s", i, expectedResults[i], result)
+		}
+	}
+}
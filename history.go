@@ -0,0 +1,51 @@
+package bible
+
+import "strings"
+
+// Lookup is a query Execute read as references and found verses for
+type Lookup struct {
+	Query string
+	// passages of the query, merged and in Bible order
+	Passages PassageSet
+	// chapters of the verses that were found, verse is 0. Context is not
+	// counted
+	Chapters []Reference
+}
+
+// SetOnLookup sets a function Execute calls with every lookup. Searches
+// and lists of books or chapters are not lookups. nil turns it off
+func (app *Bible) SetOnLookup(f func(Lookup)) *Bible {
+	app.onLookup = f
+	return app
+}
+
+func newLookup(query string, passages []Passage, verses []Verse) Lookup {
+	var lookup = Lookup{
+		Query:    query,
+		Passages: NewPassageSet(passages...),
+	}
+
+	for _, v := range verses {
+		chapter := Reference{BookNumber: v.BookNumber, Chapter: v.Chapter}
+
+		if n := len(lookup.Chapters); n > 0 && lookup.Chapters[n-1] == chapter {
+			continue
+		}
+
+		lookup.Chapters = append(lookup.Chapters, chapter)
+	}
+
+	return lookup
+}
+
+// Reference writes the passages out one by one, e.g. `John 3:16-18,
+// John 3:20, Acts 1`, so it can be read back as a query
+func (l Lookup) Reference() string {
+	var parts []string
+
+	for _, p := range l.Passages.Passages() {
+		parts = append(parts, p.String())
+	}
+
+	return strings.Join(parts, ", ")
+}
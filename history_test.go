@@ -0,0 +1,101 @@
+package bible
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestOnLookup(t *testing.T) {
+	app := testModule(t)
+
+	var lookups []Lookup
+	app.SetOnLookup(func(l Lookup) {
+		lookups = append(lookups, l)
+	})
+
+	tests := []string{
+		"John 20:2-21:1",
+		"Jn 21:2, 20:1, acts 1:1-2",
+		"text",
+		"John",
+	}
+
+	// searches, lists of chapters and references without verses are not
+	// lookups
+	expectedResults := []string{
+		"John 20:2-21:1 [John 20 John 21]",
+		"John 20:1, John 21:2, Acts 1:1-2 [John 20 John 21 Acts 1]",
+		"",
+		"",
+	}
+
+	for i, test := range tests {
+		lookups = nil
+
+		if _, err := app.SetQuery(test).Execute(); err != nil {
+			t.Fatalf("TEST[%d] failed: %s", i, err)
+		}
+
+		var result string
+		for _, l := range lookups {
+			result = fmt.Sprintf("%s %v", l.Reference(), l.Chapters)
+		}
+
+		if result != expectedResults[i] || len(lookups) > 1 {
+			t.Fatalf("TEST[%d] failed: expected %q, got %q", i, expectedResults[i], result)
+		}
+	}
+
+	// whole books are read as passages
+	lookups = nil
+	if _, err := app.SetWholeBooks(true).SetQuery("Acts").Execute(); err != nil {
+		t.Fatalf("TEST[%d] failed: %s", len(tests), err)
+	}
+
+	if len(lookups) != 1 || lookups[0].Reference() != "Acts" || lookups[0].Query != "Acts" {
+		t.Fatalf("TEST[%d] failed: expected a lookup of Acts, got %v", len(tests), lookups)
+	}
+}
+
+// history reads lookups again from their reference, so it has to give
+// the same passages back
+func TestLookupReference(t *testing.T) {
+	app := testModule(t)
+
+	tests := []string{
+		"John-Acts",
+		"John 20-Acts 1",
+		"John 21:2-Acts 1:1",
+		"John 20:2, John 21-Acts 1:2",
+	}
+
+	expectedResults := []string{
+		"John-Acts",
+		"John 20-Acts 1",
+		"John 21:2-Acts 1:1",
+		"John 20:2, John 21:1-Acts 1:2",
+	}
+
+	for i, test := range tests {
+		passages, err := app.Resolve(test)
+		if err != nil {
+			t.Fatalf("TEST[%d] failed: %s", i, err)
+		}
+
+		lookup := Lookup{Query: test, Passages: NewPassageSet(passages...)}
+		result := lookup.Reference()
+
+		if result != expectedResults[i] {
+			t.Fatalf("TEST[%d] failed: expected %q, got %q", i, expectedResults[i], result)
+		}
+
+		again, err := app.Resolve(result)
+		if err != nil {
+			t.Fatalf("TEST[%d] failed: %s", i, err)
+		}
+
+		if set := NewPassageSet(again...); set.String() != lookup.Passages.String() {
+			t.Fatalf("TEST[%d] failed: %q read back as %q", i, lookup.Passages, set)
+		}
+	}
+}
@@ -0,0 +1,319 @@
+// Package config loads bible-cli settings. Values are taken from the
+// config file first, then overridden by environment variables and
+// finally by command line flags.
+package config
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+)
+
+const (
+	APP_NAME    = "bible-cli"
+	CONFIG_FILE = "config.toml"
+)
+
+// canons the bible package knows by name
+var SHIPPED_CANONS = []string{"protestant", "catholic", "orthodox"}
+
+// Duration is time.Duration that can be read from strings like "5s"
+type Duration struct {
+	time.Duration
+}
+
+func (d *Duration) UnmarshalText(text []byte) error {
+	v, err := time.ParseDuration(string(text))
+	if err != nil {
+		return err
+	}
+	d.Duration = v
+	return nil
+}
+
+func (d Duration) MarshalText() ([]byte, error) {
+	return []byte(d.String()), nil
+}
+
+type Config struct {
+	// name of the module file without extension, e.g. ESV
+	Translation string `toml:"translation"`
+	// directories searched for modules, in order
+	ModuleDirs []string `toml:"module_dirs"`
+	Extension  string   `toml:"extension"`
+
+	// text or json
+	Format string `toml:"format"`
+	Theme  string `toml:"theme"`
+	// auto, none, 16, 256 or truecolor
+	Color string `toml:"color"`
+	// 0 is the width of the terminal, negative disables wrapping
+	Width int `toml:"width"`
+	// paragraph or verse
+	Layout    string `toml:"layout"`
+	Footnotes bool   `toml:"footnotes"`
+	Headings  bool   `toml:"headings"`
+	// show highlights and notes of the reader
+	Annotations bool `toml:"annotations"`
+	// keep lookups in the state database
+	History bool `toml:"history"`
+
+	// extra book names, alias = "Book name"
+	Aliases map[string]string `toml:"aliases"`
+	// more names of books by book number, "500" = ["Jn", "Йн"]
+	BookNames map[string][]string `toml:"book_names"`
+	// protestant, catholic, orthodox or one of canons, empty is every
+	// book of the module
+	Canon string `toml:"canon"`
+	// own canons, made of book and canon names
+	Canons map[string][]string `toml:"canons"`
+	// verse of the day is the same for everyone with the same seed
+	Seed    string   `toml:"seed"`
+	Timeout Duration `toml:"timeout"`
+
+	// where the config was read from, empty if there was no file
+	File string `toml:"-"`
+}
+
+func Default() Config {
+	return Config{
+		Translation: "ESV",
+		ModuleDirs:  []string{Dir()},
+		Extension:   "SQLite3",
+		Format:      "text",
+		Color:       "auto",
+		Layout:      "paragraph",
+		History:     true,
+		Aliases:     map[string]string{},
+		BookNames:   map[string][]string{},
+		Canons:      map[string][]string{},
+		Timeout:     Duration{5 * time.Second},
+	}
+}
+
+// returns $XDG_CONFIG_HOME/bible-cli or ~/.config/bible-cli
+func Dir() string {
+	if xdg := os.Getenv("XDG_CONFIG_HOME"); xdg != "" {
+		return filepath.Join(xdg, APP_NAME)
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return filepath.Join(".config", APP_NAME)
+	}
+
+	return filepath.Join(home, ".config", APP_NAME)
+}
+
+// returns $XDG_STATE_HOME/bible-cli or ~/.local/state/bible-cli. Things
+// like command history go there.
+func StateDir() string {
+	if xdg := os.Getenv("XDG_STATE_HOME"); xdg != "" {
+		return filepath.Join(xdg, APP_NAME)
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return filepath.Join(".local", "state", APP_NAME)
+	}
+
+	return filepath.Join(home, ".local", "state", APP_NAME)
+}
+
+// returns $BIBLE_CONFIG or config.toml in the config directory
+func Path() string {
+	if path := os.Getenv("BIBLE_CONFIG"); path != "" {
+		return path
+	}
+
+	return filepath.Join(Dir(), CONFIG_FILE)
+}
+
+// reads config file on top of the defaults. Missing file is not an error.
+func Load(path string) (Config, error) {
+	var c = Default()
+
+	_, err := toml.DecodeFile(path, &c)
+
+	if errors.Is(err, fs.ErrNotExist) {
+		return c, nil
+	}
+
+	if err != nil {
+		return c, fmt.Errorf("config %s: %w", path, err)
+	}
+
+	c.File = path
+	for i, dir := range c.ModuleDirs {
+		c.ModuleDirs[i] = expandHome(dir)
+	}
+
+	if _, err := c.BookNamesByNumber(); err != nil {
+		return c, fmt.Errorf("config %s: %w", path, err)
+	}
+
+	return c, nil
+}
+
+// returns book_names with keys read as book numbers
+func (c Config) BookNamesByNumber() (map[int][]string, error) {
+	var result = make(map[int][]string, len(c.BookNames))
+
+	for key, names := range c.BookNames {
+		number, err := strconv.Atoi(key)
+		if err != nil || number <= 0 {
+			return nil, fmt.Errorf("book_names: `%s` is not a book number", key)
+		}
+
+		result[number] = names
+	}
+
+	return result, nil
+}
+
+// returns what the canon is made of: books and canons from canons or
+// the name itself for the shipped ones
+func (c Config) CanonEntries() ([]string, error) {
+	if c.Canon == "" {
+		return nil, nil
+	}
+
+	if entries, ok := c.Canons[c.Canon]; ok {
+		if len(entries) < 1 {
+			return nil, fmt.Errorf("canons: `%s` has no books", c.Canon)
+		}
+		return entries, nil
+	}
+
+	for _, name := range SHIPPED_CANONS {
+		if strings.EqualFold(name, c.Canon) {
+			return []string{name}, nil
+		}
+	}
+
+	return nil, fmt.Errorf("unknown canon `%s`, expected %s or one of canons",
+		c.Canon, strings.Join(SHIPPED_CANONS, ", "))
+}
+
+func expandHome(path string) string {
+	rest, found := strings.CutPrefix(path, "~")
+	if !found {
+		return path
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return path
+	}
+
+	return filepath.Join(home, rest)
+}
+
+// overrides values with the environment variables. BIBLECLI, TRANSLATION
+// and BIBLE_ENV are kept for compatibility with older versions.
+func (c *Config) ApplyEnv(getenv func(string) string) error {
+	if v := getenv("BIBLECLI"); v != "" {
+		c.ModuleDirs = filepath.SplitList(v)
+	}
+
+	if v := getenv("TRANSLATION"); v != "" {
+		c.Translation = v
+	}
+
+	if v := getenv("BIBLE_FORMAT"); v != "" {
+		c.Format = v
+	}
+
+	if v := getenv("BIBLE_THEME"); v != "" {
+		c.Theme = v
+	}
+
+	if v := getenv("BIBLE_COLOR"); v != "" {
+		c.Color = v
+	}
+
+	if getenv("BIBLE_ENV") != "" {
+		c.Color = "none"
+	}
+
+	if v := getenv("BIBLE_LAYOUT"); v != "" {
+		c.Layout = v
+	}
+
+	if v := getenv("BIBLE_WIDTH"); v != "" {
+		width, err := strconv.Atoi(v)
+		if err != nil {
+			return fmt.Errorf("BIBLE_WIDTH must be a number: %w", err)
+		}
+		c.Width = width
+	}
+
+	if v := getenv("BIBLE_CANON"); v != "" {
+		c.Canon = v
+	}
+
+	if v := getenv("BIBLE_SEED"); v != "" {
+		c.Seed = v
+	}
+
+	if v := getenv("BIBLE_ANNOTATIONS"); v != "" {
+		annotations, err := strconv.ParseBool(v)
+		if err != nil {
+			return fmt.Errorf("BIBLE_ANNOTATIONS must be true or false: %w", err)
+		}
+		c.Annotations = annotations
+	}
+
+	if v := getenv("BIBLE_HISTORY"); v != "" {
+		history, err := strconv.ParseBool(v)
+		if err != nil {
+			return fmt.Errorf("BIBLE_HISTORY must be true or false: %w", err)
+		}
+		c.History = history
+	}
+
+	if v := getenv("BIBLE_TIMEOUT"); v != "" {
+		if err := c.Timeout.UnmarshalText([]byte(v)); err != nil {
+			return fmt.Errorf("BIBLE_TIMEOUT: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// finds module file of the translation in the module directories
+func (c Config) DatabasePath() (string, error) {
+	name := fmt.Sprintf("%s.%s", c.Translation, c.Extension)
+
+	for _, dir := range c.ModuleDirs {
+		path := filepath.Join(dir, name)
+
+		if _, err := os.Stat(path); err == nil {
+			return path, nil
+		}
+	}
+
+	return "", fmt.Errorf("translation %s not found in %s",
+		name,
+		strings.Join(c.ModuleDirs, ", "),
+	)
+}
+
+// writes effective configuration in the same format it is read
+func (c Config) Write(w io.Writer) error {
+	if c.File == "" {
+		fmt.Fprintf(w, "# %s not found, using defaults\n", Path())
+	} else {
+		fmt.Fprintf(w, "# %s\n", c.File)
+	}
+
+	return toml.NewEncoder(w).Encode(c)
+}
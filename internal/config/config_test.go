@@ -0,0 +1,142 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLoad(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, CONFIG_FILE)
+
+	file := `
+translation = "NIV"
+module_dirs = ["/a", "/b"]
+width = 72
+headings = true
+timeout = "10s"
+
+[aliases]
+jn = "John"
+
+[book_names]
+500 = ["Йн", "Jhn"]
+`
+	if err := os.WriteFile(path, []byte(file), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	c, err := Load(path)
+	if err != nil {
+		t.Fatalf("failed to load config: %s", err)
+	}
+
+	if c.Translation != "NIV" || c.Width != 72 || !c.Headings {
+		t.Fatalf("values were not read from the file: %#v", c)
+	}
+
+	if len(c.ModuleDirs) != 2 || c.ModuleDirs[1] != "/b" {
+		t.Fatalf("expected two module dirs got %v", c.ModuleDirs)
+	}
+
+	if c.Timeout.Duration != 10*time.Second {
+		t.Fatalf("expected timeout 10s got %s", c.Timeout)
+	}
+
+	if c.Aliases["jn"] != "John" {
+		t.Fatalf("expected alias jn got %v", c.Aliases)
+	}
+
+	names, err := c.BookNamesByNumber()
+	if err != nil || len(names[500]) != 2 {
+		t.Fatalf("expected two names of book 500 got %v %v", names, err)
+	}
+
+	// not in the file, so should be the default
+	if c.Extension != "SQLite3" || c.Format != "text" {
+		t.Fatalf("defaults were lost: %#v", c)
+	}
+}
+
+func TestLoadMissingFile(t *testing.T) {
+	c, err := Load(filepath.Join(t.TempDir(), "nope.toml"))
+	if err != nil {
+		t.Fatalf("missing file should not be an error: %s", err)
+	}
+
+	if c.File != "" || c.Translation != "ESV" {
+		t.Fatalf("expected defaults got %#v", c)
+	}
+}
+
+func TestLoadMalformedFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), CONFIG_FILE)
+	os.WriteFile(path, []byte("width = \"wide\""), 0o644)
+
+	if _, err := Load(path); err == nil {
+		t.Fatalf("expected an error for malformed config")
+	}
+}
+
+func TestApplyEnv(t *testing.T) {
+	env := map[string]string{
+		"BIBLECLI":          "/x" + string(os.PathListSeparator) + "/y",
+		"TRANSLATION":       "RST",
+		"BIBLE_ENV":         "plain",
+		"BIBLE_WIDTH":       "40",
+		"BIBLE_TIMEOUT":     "1m",
+		"BIBLE_CANON":       "protestant",
+		"BIBLE_ANNOTATIONS": "true",
+		"BIBLE_HISTORY":     "false",
+	}
+
+	c := Default()
+	if err := c.ApplyEnv(func(s string) string { return env[s] }); err != nil {
+		t.Fatal(err)
+	}
+
+	if strings.Join(c.ModuleDirs, ",") != "/x,/y" {
+		t.Fatalf("expected module dirs from BIBLECLI got %v", c.ModuleDirs)
+	}
+
+	if c.Translation != "RST" || c.Color != "none" || c.Width != 40 || c.Canon != "protestant" || !c.Annotations || c.History {
+		t.Fatalf("env was not applied: %#v", c)
+	}
+
+	if c.Timeout.Duration != time.Minute {
+		t.Fatalf("expected timeout 1m got %s", c.Timeout)
+	}
+
+	env["BIBLE_WIDTH"] = "wide"
+	if err := c.ApplyEnv(func(s string) string { return env[s] }); err == nil {
+		t.Fatalf("expected an error for BIBLE_WIDTH=wide")
+	}
+}
+
+func TestCanonEntries(t *testing.T) {
+	c := Default()
+	c.Canons = map[string][]string{
+		"study": {"protestant", "Tobit"},
+		"empty": {},
+	}
+
+	tests := []string{"", "Catholic", "study", "empty", "lutheran"}
+
+	expectedResults := []string{"", "catholic", "protestant,Tobit", "error", "error"}
+
+	for i, test := range tests {
+		c.Canon = test
+
+		result := "error"
+		if entries, err := c.CanonEntries(); err == nil {
+			result = strings.Join(entries, ",")
+		}
+
+		if result != expectedResults[i] {
+			t.Fatalf("TEST[%d] failed: expected %q got %q", i, expectedResults[i], result)
+		}
+	}
+}
@@ -0,0 +1,171 @@
+package plan
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+type book struct {
+	name     string
+	chapters int
+}
+
+// books of the Protestant canon with their chapters
+var books = []book{
+	{"Genesis", 50}, {"Exodus", 40}, {"Leviticus", 27}, {"Numbers", 36},
+	{"Deuteronomy", 34}, {"Joshua", 24}, {"Judges", 21}, {"Ruth", 4},
+	{"1 Samuel", 31}, {"2 Samuel", 24}, {"1 Kings", 22}, {"2 Kings", 25},
+	{"1 Chronicles", 29}, {"2 Chronicles", 36}, {"Ezra", 10}, {"Nehemiah", 13},
+	{"Esther", 10}, {"Job", 42}, {"Psalms", 150}, {"Proverbs", 31},
+	{"Ecclesiastes", 12}, {"Song of Solomon", 8}, {"Isaiah", 66}, {"Jeremiah", 52},
+	{"Lamentations", 5}, {"Ezekiel", 48}, {"Daniel", 12}, {"Hosea", 14},
+	{"Joel", 3}, {"Amos", 9}, {"Obadiah", 1}, {"Jonah", 4},
+	{"Micah", 7}, {"Nahum", 3}, {"Habakkuk", 3}, {"Zephaniah", 3},
+	{"Haggai", 2}, {"Zechariah", 14}, {"Malachi", 4},
+	{"Matthew", 28}, {"Mark", 16}, {"Luke", 24}, {"John", 21},
+	{"Acts", 28}, {"Romans", 16}, {"1 Corinthians", 16}, {"2 Corinthians", 13},
+	{"Galatians", 6}, {"Ephesians", 6}, {"Philippians", 4}, {"Colossians", 4},
+	{"1 Thessalonians", 5}, {"2 Thessalonians", 3}, {"1 Timothy", 6}, {"2 Timothy", 4},
+	{"Titus", 3}, {"Philemon", 1}, {"Hebrews", 13}, {"James", 5},
+	{"1 Peter", 5}, {"2 Peter", 3}, {"1 John", 5}, {"2 John", 1},
+	{"3 John", 1}, {"Jude", 1}, {"Revelation", 22},
+}
+
+type chapter struct {
+	book string
+	n    int
+}
+
+// chapters of the books from one to another, both included
+func chapters(from, to string) []chapter {
+	i := slices.IndexFunc(books, func(b book) bool { return b.name == from })
+	j := slices.IndexFunc(books, func(b book) bool { return b.name == to })
+
+	var result []chapter
+	for _, b := range books[i : j+1] {
+		for n := 1; n <= b.chapters; n++ {
+			result = append(result, chapter{b.name, n})
+		}
+	}
+
+	return result
+}
+
+// splits chapters into days as evenly as they go. Bounds are rounded up
+// so a track shorter than the plan still starts on day one
+func spread(chs []chapter, days int) [][]string {
+	var result = make([][]string, days)
+
+	bound := func(d int) int {
+		return (d*len(chs) + days - 1) / days
+	}
+
+	for d := range days {
+		result[d] = readings(chs[bound(d):bound(d+1)])
+	}
+
+	return result
+}
+
+// chapters of one book that follow each other are one reading:
+// `Genesis 1-3`
+func readings(chs []chapter) []string {
+	var result []string
+
+	for i := 0; i < len(chs); {
+		j := i
+		for j+1 < len(chs) && chs[j+1].book == chs[i].book {
+			j++
+		}
+
+		if i == j {
+			result = append(result, fmt.Sprintf("%s %d", chs[i].book, chs[i].n))
+		} else {
+			result = append(result, fmt.Sprintf("%s %d-%d", chs[i].book, chs[i].n, chs[j].n))
+		}
+
+		i = j + 1
+	}
+
+	return result
+}
+
+// days of parallel tracks go together
+func merge(tracks ...[][]string) [][]string {
+	var result = make([][]string, len(tracks[0]))
+
+	for _, track := range tracks {
+		for d, r := range track {
+			result[d] = append(result[d], r...)
+		}
+	}
+
+	return result
+}
+
+// the whole Bible, from Genesis to Revelation
+func bibleInAYear() Plan {
+	return Plan{
+		Name:  "year",
+		Title: "Bible in a year",
+		Days:  spread(chapters("Genesis", "Revelation"), 365),
+	}
+}
+
+// four readings a day the way M'Cheyne laid them out: the Old Testament
+// once, the New Testament and Psalms twice
+func mcheyne() Plan {
+	return Plan{
+		Name:  "mcheyne",
+		Title: "M'Cheyne",
+		Days: merge(
+			spread(slices.Concat(chapters("Genesis", "2 Chronicles"), chapters("Psalms", "Psalms")), 365),
+			spread(chapters("Matthew", "Revelation"), 365),
+			spread(chapters("Ezra", "Malachi"), 365),
+			spread(slices.Concat(chapters("Acts", "Revelation"), chapters("Matthew", "John")), 365),
+		),
+	}
+}
+
+func newTestamentIn90Days() Plan {
+	return Plan{
+		Name:  "nt90",
+		Title: "New Testament in 90 days",
+		Days:  spread(chapters("Matthew", "Revelation"), 90),
+	}
+}
+
+// five psalms and a chapter of Proverbs a day, over and over
+func psalmsAndProverbs() Plan {
+	var days = make([][]string, 31)
+
+	for d := range days {
+		if d < 30 {
+			var psalms []string
+			for n := d + 1; n <= 150; n += 30 {
+				psalms = append(psalms, fmt.Sprint(n))
+			}
+			days[d] = append(days[d], "Psalms "+strings.Join(psalms, ", "))
+		}
+
+		days[d] = append(days[d], fmt.Sprintf("Proverbs %d", d+1))
+	}
+
+	return Plan{
+		Name:   "psalms-proverbs",
+		Title:  "Psalms and Proverbs monthly",
+		Days:   days,
+		Repeat: true,
+	}
+}
+
+// Builtin returns plans that come with bible-cli
+func Builtin() []Plan {
+	return []Plan{
+		bibleInAYear(),
+		mcheyne(),
+		newTestamentIn90Days(),
+		psalmsAndProverbs(),
+	}
+}
@@ -0,0 +1,136 @@
+// Package plan has reading plans and keeps track of the day a reader
+// is on. Readings are references the way `bible` reads them, so they
+// work with any translation.
+package plan
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// user plans are files with this extension in the plans directory
+const PLAN_EXT = ".txt"
+
+// Plan is a list of readings for every day
+type Plan struct {
+	Name  string
+	Title string
+	Days  [][]string
+	// starts over after the last day
+	Repeat bool
+}
+
+func (p Plan) Len() int {
+	return len(p.Days)
+}
+
+// Readings returns readings of the day, days start at 1
+func (p Plan) Readings(day int) []string {
+	if day < 1 || p.Len() == 0 {
+		return nil
+	}
+
+	if p.Repeat {
+		day = (day-1)%p.Len() + 1
+	}
+
+	if day > p.Len() {
+		return nil
+	}
+
+	return p.Days[day-1]
+}
+
+// Parse reads a plan file. Every line is a day with readings separated
+// by `;`, the first comment is the title:
+//
+//	# Gospel of John in three weeks
+//	John 1
+//	John 2; Psalms 1
+func Parse(name string, r io.Reader) (Plan, error) {
+	var p = Plan{Name: name, Title: name}
+	var titled bool
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+
+		if comment, ok := strings.CutPrefix(line, "#"); ok {
+			if !titled {
+				p.Title = strings.TrimSpace(comment)
+				titled = true
+			}
+			continue
+		}
+
+		if line == "" {
+			continue
+		}
+
+		var readings []string
+		for _, r := range strings.Split(line, ";") {
+			if r = strings.TrimSpace(r); r != "" {
+				readings = append(readings, r)
+			}
+		}
+
+		p.Days = append(p.Days, readings)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return p, err
+	}
+
+	if p.Len() == 0 {
+		return p, fmt.Errorf("plan %s has no readings", name)
+	}
+
+	return p, nil
+}
+
+// Load reads user plans from the directory. Missing directory has no
+// plans
+func Load(dir string) ([]Plan, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*"+PLAN_EXT))
+	if err != nil {
+		return nil, err
+	}
+
+	var result []Plan
+
+	for _, path := range files {
+		f, err := os.Open(path)
+		if err != nil {
+			return nil, err
+		}
+
+		p, err := Parse(strings.TrimSuffix(filepath.Base(path), PLAN_EXT), f)
+		f.Close()
+
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+
+		result = append(result, p)
+	}
+
+	return result, nil
+}
+
+// Find returns the plan by name. User plans win over built-in ones
+func Find(plans []Plan, name string) (Plan, bool) {
+	i := slices.IndexFunc(plans, func(p Plan) bool {
+		return strings.EqualFold(p.Name, name)
+	})
+
+	if i < 0 {
+		return Plan{}, false
+	}
+
+	return plans[i], true
+}
@@ -0,0 +1,92 @@
+package plan
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []string{
+		"# John in three weeks\nJohn 1\n\nJohn 2; Psalms 1 ;\n# not a title\nJohn 3",
+		"John 1",
+		"# only a title",
+	}
+
+	expectedResults := []string{
+		"John in three weeks [[John 1] [John 2 Psalms 1] [John 3]] <nil>",
+		"john [[John 1]] <nil>",
+		"only a title [] plan john has no readings",
+	}
+
+	for i, test := range tests {
+		p, err := Parse("john", strings.NewReader(test))
+		result := fmt.Sprintf("%s %v %v", p.Title, p.Days, err)
+
+		if result != expectedResults[i] {
+			t.Fatalf("TEST[%d] failed: expected %q, got %q", i, expectedResults[i], result)
+		}
+	}
+}
+
+func TestReadings(t *testing.T) {
+	once := Plan{Days: [][]string{{"John 1"}, {"John 2"}}}
+	again := Plan{Days: once.Days, Repeat: true}
+
+	tests := []struct {
+		plan Plan
+		day  int
+	}{
+		{once, 0},
+		{once, 1},
+		{once, 2},
+		{once, 3},
+		{again, 3},
+		{again, 6},
+	}
+
+	expectedResults := []string{"[]", "[John 1]", "[John 2]", "[]", "[John 1]", "[John 2]"}
+
+	for i, test := range tests {
+		result := fmt.Sprint(test.plan.Readings(test.day))
+
+		if result != expectedResults[i] {
+			t.Fatalf("TEST[%d] failed: expected %s, got %s", i, expectedResults[i], result)
+		}
+	}
+}
+
+func TestBuiltin(t *testing.T) {
+	plans := Builtin()
+
+	expectedResults := []string{
+		"year 365 [Genesis 1-4] [Revelation 20-22]",
+		"mcheyne 365 [Genesis 1-2 Matthew 1 Ezra 1-2 Acts 1] [Psalms 150 Malachi 4]",
+		"nt90 90 [Matthew 1-3] [Revelation 21-22]",
+		"psalms-proverbs 31 [Psalms 1, 31, 61, 91, 121 Proverbs 1] [Proverbs 31]",
+	}
+
+	for i, p := range plans {
+		result := fmt.Sprintf("%s %d %v %v", p.Name, p.Len(), p.Readings(1), p.Readings(p.Len()))
+
+		if result != expectedResults[i] {
+			t.Fatalf("TEST[%d] failed: expected %q, got %q", i, expectedResults[i], result)
+		}
+	}
+
+	// every chapter is read once
+	var count int
+	for _, day := range plans[0].Days {
+		for _, r := range day {
+			var from, to int
+			if _, err := fmt.Sscanf(r[strings.LastIndex(r, " ")+1:], "%d-%d", &from, &to); err != nil {
+				to = from
+			}
+			count += to - from + 1
+		}
+	}
+
+	if count != 1189 {
+		t.Fatalf("TEST[%d] failed: expected 1189 chapters, got %d", len(plans), count)
+	}
+}
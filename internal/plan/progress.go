@@ -0,0 +1,102 @@
+package plan
+
+import (
+	"slices"
+	"time"
+)
+
+// Progress is where a reader is in a plan. Day one is the start date,
+// every next date is the next day unless the plan is paused
+type Progress struct {
+	Start time.Time
+	// zero when the plan is not paused
+	PausedAt time.Time
+	// days the plan was paused before, they do not count
+	PausedDays int
+	Done       []int
+}
+
+// date without time, so days are counted the way a calendar counts them
+func Date(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+func daysBetween(from, to time.Time) int {
+	return int(Date(to).Sub(Date(from)).Hours() / 24)
+}
+
+func (p Progress) Paused() bool {
+	return !p.PausedAt.IsZero()
+}
+
+// Day returns the day of the plan scheduled for the date. Paused plans
+// stay on the day they were paused on
+func (p Progress) Day(date time.Time) int {
+	if p.Paused() {
+		date = p.PausedAt
+	}
+
+	return max(daysBetween(p.Start, date)-p.PausedDays+1, 1)
+}
+
+func (p Progress) IsDone(day int) bool {
+	return slices.Contains(p.Done, day)
+}
+
+// Behind returns days before the one of the date that are not done yet.
+// Days after the last one of the plan do not count
+func (p Progress) Behind(date time.Time, plan Plan) []int {
+	last := p.Day(date) - 1
+	if !plan.Repeat {
+		last = min(last, plan.Len())
+	}
+
+	var result []int
+	for day := 1; day <= last; day++ {
+		if !p.IsDone(day) {
+			result = append(result, day)
+		}
+	}
+
+	return result
+}
+
+// Finished reports whether every day of the plan is done
+func (p Progress) Finished(plan Plan) bool {
+	if plan.Repeat {
+		return false
+	}
+
+	for day := 1; day <= plan.Len(); day++ {
+		if !p.IsDone(day) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (p *Progress) Pause(date time.Time) {
+	if !p.Paused() {
+		p.PausedAt = Date(date)
+	}
+}
+
+// Resume skips days the plan was paused for
+func (p *Progress) Resume(date time.Time) {
+	if p.Paused() {
+		p.PausedDays += max(daysBetween(p.PausedAt, date), 0)
+		p.PausedAt = time.Time{}
+	}
+}
+
+// CatchUp moves the schedule so the first day that is not done is the
+// day of the date
+func (p *Progress) CatchUp(date time.Time, plan Plan) {
+	behind := p.Behind(date, plan)
+	if len(behind) == 0 {
+		return
+	}
+
+	p.PausedDays += p.Day(date) - behind[0]
+}
@@ -0,0 +1,80 @@
+package plan
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func day(d int) time.Time {
+	return time.Date(2026, time.January, d, 9, 30, 0, 0, time.Local)
+}
+
+func TestProgress(t *testing.T) {
+	p := Plan{Days: make([][]string, 5)}
+
+	tests := []struct {
+		progress Progress
+		date     time.Time
+	}{
+		{Progress{Start: day(1)}, day(1)},
+		{Progress{Start: day(1)}, day(3)},
+		{Progress{Start: day(5)}, day(3)},
+		{Progress{Start: day(1), Done: []int{1, 2}}, day(4)},
+		{Progress{Start: day(1), PausedAt: day(2)}, day(9)},
+		{Progress{Start: day(1), PausedDays: 2}, day(4)},
+		{Progress{Start: day(1), Done: []int{1, 3}}, day(20)},
+		{Progress{Start: day(1), Done: []int{1, 2, 3, 4, 5}}, day(20)},
+	}
+
+	expectedResults := []string{
+		"day 1 behind [] finished false",
+		"day 3 behind [1 2] finished false",
+		"day 1 behind [] finished false",
+		"day 4 behind [3] finished false",
+		"day 2 behind [1] finished false",
+		"day 2 behind [1] finished false",
+		"day 20 behind [2 4 5] finished false",
+		"day 20 behind [] finished true",
+	}
+
+	for i, test := range tests {
+		pr := test.progress
+		result := fmt.Sprintf("day %d behind %v finished %v", pr.Day(test.date), pr.Behind(test.date, p), pr.Finished(p))
+
+		if result != expectedResults[i] {
+			t.Fatalf("TEST[%d] failed: expected %q, got %q", i, expectedResults[i], result)
+		}
+	}
+}
+
+func TestPauseAndCatchUp(t *testing.T) {
+	p := Plan{Days: make([][]string, 30)}
+
+	var progress = Progress{Start: day(1), Done: []int{1, 2}}
+
+	progress.Pause(day(3))
+	progress.Pause(day(5))
+	if d := progress.Day(day(10)); d != 3 {
+		t.Fatalf("TEST[0] failed: expected day 3 while paused, got %d", d)
+	}
+
+	progress.Resume(day(10))
+	if d := progress.Day(day(10)); d != 3 || progress.Paused() {
+		t.Fatalf("TEST[1] failed: expected day 3 after resume, got %d", d)
+	}
+
+	progress.Done = append(progress.Done, 3)
+	if d := progress.Day(day(15)); d != 8 {
+		t.Fatalf("TEST[2] failed: expected day 8, got %d", d)
+	}
+
+	progress.CatchUp(day(15), p)
+	if d := progress.Day(day(15)); d != 4 {
+		t.Fatalf("TEST[3] failed: expected day 4 after catch up, got %d", d)
+	}
+
+	if behind := progress.Behind(day(15), p); len(behind) != 0 {
+		t.Fatalf("TEST[4] failed: expected nothing behind, got %v", behind)
+	}
+}
@@ -0,0 +1,60 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"iter"
+)
+
+// sqlc has no iterators, so these are written by hand on top of the
+// generated queries. Rows are read one at a time and the query runs
+// only when the sequence is ranged over.
+
+func (q *Queries) IterVersesBetween(ctx context.Context, arg GetVersesBetweenParams) iter.Seq2[Verse, error] {
+	return func(yield func(Verse, error) bool) {
+		rows, err := q.db.QueryContext(ctx, getVersesBetween,
+			arg.FromBook,
+			arg.FromChapter,
+			arg.FromVerse,
+			arg.ToBook,
+			arg.ToChapter,
+			arg.ToVerse,
+		)
+		yieldVerses(rows, err, yield)
+	}
+}
+
+func (q *Queries) IterSearch(ctx context.Context, text string) iter.Seq2[Verse, error] {
+	return func(yield func(Verse, error) bool) {
+		rows, err := q.db.QueryContext(ctx, search, text)
+		yieldVerses(rows, err, yield)
+	}
+}
+
+func yieldVerses(rows *sql.Rows, err error, yield func(Verse, error) bool) {
+	if err != nil {
+		yield(Verse{}, err)
+		return
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var i Verse
+		if err := rows.Scan(
+			&i.BookNumber,
+			&i.Chapter,
+			&i.Verse,
+			&i.Text,
+		); err != nil {
+			yield(Verse{}, err)
+			return
+		}
+		if !yield(i, nil) {
+			return
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		yield(Verse{}, err)
+	}
+}
@@ -9,6 +9,35 @@ import (
 	"context"
 )
 
+const countVersesBetween = `-- name: CountVersesBetween :one
+SELECT COUNT(*) FROM verses
+WHERE (book_number, chapter, verse) >= (?, ?, ?)
+AND (book_number, chapter, verse) <= (?, ?, ?)
+`
+
+type CountVersesBetweenParams struct {
+	FromBook    float64
+	FromChapter float64
+	FromVerse   float64
+	ToBook      float64
+	ToChapter   float64
+	ToVerse     float64
+}
+
+func (q *Queries) CountVersesBetween(ctx context.Context, arg CountVersesBetweenParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countVersesBetween,
+		arg.FromBook,
+		arg.FromChapter,
+		arg.FromVerse,
+		arg.ToBook,
+		arg.ToChapter,
+		arg.ToVerse,
+	)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
 const getBookNames = `-- name: GetBookNames :many
 SELECT book_number, short_name, long_name, book_color FROM books ORDER BY book_number
 `
@@ -41,6 +70,89 @@ func (q *Queries) GetBookNames(ctx context.Context) ([]Book, error) {
 	return items, nil
 }
 
+const getBookStats = `-- name: GetBookStats :many
+SELECT book_number, COUNT(DISTINCT chapter) AS chapters, COUNT(*) AS verses
+FROM verses
+GROUP BY book_number
+ORDER BY book_number
+`
+
+type GetBookStatsRow struct {
+	BookNumber float64
+	Chapters   int64
+	Verses     int64
+}
+
+func (q *Queries) GetBookStats(ctx context.Context) ([]GetBookStatsRow, error) {
+	rows, err := q.db.QueryContext(ctx, getBookStats)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetBookStatsRow
+	for rows.Next() {
+		var i GetBookStatsRow
+		if err := rows.Scan(&i.BookNumber, &i.Chapters, &i.Verses); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getBookStories = `-- name: GetBookStories :many
+SELECT book_number, chapter, verse, order_if_several, title FROM stories
+WHERE (book_number = ?)
+ORDER BY book_number, chapter, verse, order_if_several
+`
+
+func (q *Queries) GetBookStories(ctx context.Context, bookNumber float64) ([]Story, error) {
+	rows, err := q.db.QueryContext(ctx, getBookStories, bookNumber)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Story
+	for rows.Next() {
+		var i Story
+		if err := rows.Scan(
+			&i.BookNumber,
+			&i.Chapter,
+			&i.Verse,
+			&i.OrderIfSeveral,
+			&i.Title,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getChapterCount = `-- name: GetChapterCount :one
+SELECT COUNT(DISTINCT chapter) FROM verses
+WHERE (book_number = ?)
+`
+
+func (q *Queries) GetChapterCount(ctx context.Context, bookNumber float64) (int64, error) {
+	row := q.db.QueryRowContext(ctx, getChapterCount, bookNumber)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
 const getChapters = `-- name: GetChapters :many
 SELECT book_number, chapter, verse, text FROM verses
 WHERE (book_number = ?)
@@ -76,6 +188,225 @@ func (q *Queries) GetChapters(ctx context.Context, bookNumber float64) ([]Verse,
 	return items, nil
 }
 
+const getInfo = `-- name: GetInfo :many
+SELECT name, value FROM info ORDER BY name
+`
+
+func (q *Queries) GetInfo(ctx context.Context) ([]Info, error) {
+	rows, err := q.db.QueryContext(ctx, getInfo)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Info
+	for rows.Next() {
+		var i Info
+		if err := rows.Scan(&i.Name, &i.Value); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getStories = `-- name: GetStories :many
+SELECT book_number, chapter, verse, order_if_several, title FROM stories
+WHERE (book_number = ?)
+AND (chapter = ?)
+ORDER BY book_number, chapter, verse, order_if_several
+`
+
+type GetStoriesParams struct {
+	BookNumber float64
+	Chapter    float64
+}
+
+func (q *Queries) GetStories(ctx context.Context, arg GetStoriesParams) ([]Story, error) {
+	rows, err := q.db.QueryContext(ctx, getStories, arg.BookNumber, arg.Chapter)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Story
+	for rows.Next() {
+		var i Story
+		if err := rows.Scan(
+			&i.BookNumber,
+			&i.Chapter,
+			&i.Verse,
+			&i.OrderIfSeveral,
+			&i.Title,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getVerseAfter = `-- name: GetVerseAfter :one
+SELECT book_number, chapter, verse, text FROM verses
+WHERE (book_number, chapter, verse) > (?, ?, ?)
+ORDER BY book_number, chapter, verse
+LIMIT 1 OFFSET ?
+`
+
+type GetVerseAfterParams struct {
+	BookNumber float64
+	Chapter    float64
+	Verse      float64
+	Offset     int64
+}
+
+func (q *Queries) GetVerseAfter(ctx context.Context, arg GetVerseAfterParams) (Verse, error) {
+	row := q.db.QueryRowContext(ctx, getVerseAfter,
+		arg.BookNumber,
+		arg.Chapter,
+		arg.Verse,
+		arg.Offset,
+	)
+	var i Verse
+	err := row.Scan(
+		&i.BookNumber,
+		&i.Chapter,
+		&i.Verse,
+		&i.Text,
+	)
+	return i, err
+}
+
+const getVerseBefore = `-- name: GetVerseBefore :one
+SELECT book_number, chapter, verse, text FROM verses
+WHERE (book_number, chapter, verse) < (?, ?, ?)
+ORDER BY book_number DESC, chapter DESC, verse DESC
+LIMIT 1 OFFSET ?
+`
+
+type GetVerseBeforeParams struct {
+	BookNumber float64
+	Chapter    float64
+	Verse      float64
+	Offset     int64
+}
+
+func (q *Queries) GetVerseBefore(ctx context.Context, arg GetVerseBeforeParams) (Verse, error) {
+	row := q.db.QueryRowContext(ctx, getVerseBefore,
+		arg.BookNumber,
+		arg.Chapter,
+		arg.Verse,
+		arg.Offset,
+	)
+	var i Verse
+	err := row.Scan(
+		&i.BookNumber,
+		&i.Chapter,
+		&i.Verse,
+		&i.Text,
+	)
+	return i, err
+}
+
+const getVerseBetweenAt = `-- name: GetVerseBetweenAt :one
+SELECT book_number, chapter, verse, text FROM verses
+WHERE (book_number, chapter, verse) >= (?, ?, ?)
+AND (book_number, chapter, verse) <= (?, ?, ?)
+ORDER BY book_number, chapter, verse
+LIMIT 1 OFFSET ?
+`
+
+type GetVerseBetweenAtParams struct {
+	FromBook    float64
+	FromChapter float64
+	FromVerse   float64
+	ToBook      float64
+	ToChapter   float64
+	ToVerse     float64
+	Offset      int64
+}
+
+func (q *Queries) GetVerseBetweenAt(ctx context.Context, arg GetVerseBetweenAtParams) (Verse, error) {
+	row := q.db.QueryRowContext(ctx, getVerseBetweenAt,
+		arg.FromBook,
+		arg.FromChapter,
+		arg.FromVerse,
+		arg.ToBook,
+		arg.ToChapter,
+		arg.ToVerse,
+		arg.Offset,
+	)
+	var i Verse
+	err := row.Scan(
+		&i.BookNumber,
+		&i.Chapter,
+		&i.Verse,
+		&i.Text,
+	)
+	return i, err
+}
+
+const getVersesBetween = `-- name: GetVersesBetween :many
+SELECT book_number, chapter, verse, text FROM verses
+WHERE (book_number, chapter, verse) >= (?, ?, ?)
+AND (book_number, chapter, verse) <= (?, ?, ?)
+ORDER BY book_number, chapter, verse
+`
+
+type GetVersesBetweenParams struct {
+	FromBook    float64
+	FromChapter float64
+	FromVerse   float64
+	ToBook      float64
+	ToChapter   float64
+	ToVerse     float64
+}
+
+func (q *Queries) GetVersesBetween(ctx context.Context, arg GetVersesBetweenParams) ([]Verse, error) {
+	rows, err := q.db.QueryContext(ctx, getVersesBetween,
+		arg.FromBook,
+		arg.FromChapter,
+		arg.FromVerse,
+		arg.ToBook,
+		arg.ToChapter,
+		arg.ToVerse,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Verse
+	for rows.Next() {
+		var i Verse
+		if err := rows.Scan(
+			&i.BookNumber,
+			&i.Chapter,
+			&i.Verse,
+			&i.Text,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getVersesCollection = `-- name: GetVersesCollection :many
 SELECT book_number, chapter, verse, text FROM verses
 WHERE (book_number = ?)
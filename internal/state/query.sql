@@ -0,0 +1,107 @@
+-- name: StartPlan :exec
+INSERT OR REPLACE INTO plans (name, start, paused_at, paused_days)
+VALUES (?, ?, '', 0);
+
+-- name: GetPlan :one
+SELECT * FROM plans
+WHERE (name = ?);
+
+-- name: ListPlans :many
+SELECT * FROM plans ORDER BY name;
+
+-- name: UpdatePlan :exec
+UPDATE plans
+SET start = ?, paused_at = ?, paused_days = ?
+WHERE (name = ?);
+
+-- name: DeletePlan :exec
+DELETE FROM plans
+WHERE (name = ?);
+
+-- name: MarkPlanDay :exec
+INSERT OR REPLACE INTO plan_days (plan, day, done_at)
+VALUES (?, ?, ?);
+
+-- name: GetPlanDays :many
+SELECT * FROM plan_days
+WHERE (plan = ?)
+ORDER BY day;
+
+-- name: ClearPlanDays :exec
+DELETE FROM plan_days
+WHERE (plan = ?);
+
+-- name: AddAnnotation :one
+INSERT INTO annotations (
+        kind, from_book, from_chapter, from_verse,
+        to_book, to_chapter, to_verse, value, created_at)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+RETURNING id;
+
+-- name: DeleteAnnotation :execrows
+DELETE FROM annotations
+WHERE (id = ? AND kind = ?);
+
+-- name: DeleteAnnotationsAt :execrows
+DELETE FROM annotations
+WHERE (kind = ?
+        AND from_book = ? AND from_chapter = ? AND from_verse = ?
+        AND to_book = ? AND to_chapter = ? AND to_verse = ?);
+
+-- name: DeleteAnnotationValue :execrows
+DELETE FROM annotations
+WHERE (kind = ?
+        AND from_book = ? AND from_chapter = ? AND from_verse = ?
+        AND to_book = ? AND to_chapter = ? AND to_verse = ?
+        AND value = ?);
+
+-- name: GetBookAnnotations :many
+SELECT * FROM annotations
+WHERE (from_book <= ? AND to_book >= ?)
+ORDER BY id;
+
+-- name: ListAnnotations :many
+SELECT * FROM annotations
+WHERE (kind = ?)
+ORDER BY from_book, from_chapter, from_verse, id;
+
+-- name: ListAnnotationsByValue :many
+SELECT * FROM annotations
+WHERE (kind = ? AND value = ?)
+ORDER BY from_book, from_chapter, from_verse, id;
+
+-- name: ListTags :many
+SELECT value, COUNT(*) AS count FROM annotations
+WHERE (kind = 'tag')
+GROUP BY value
+ORDER BY value;
+
+-- name: AddHistory :one
+INSERT INTO history (query, reference, translation, created_at)
+VALUES (?, ?, ?, ?)
+RETURNING id;
+
+-- name: AddHistoryChapter :exec
+INSERT OR IGNORE INTO history_chapters (history, book, chapter)
+VALUES (?, ?, ?);
+
+-- name: GetHistory :one
+SELECT * FROM history
+WHERE (id = ?);
+
+-- name: ListHistory :many
+SELECT * FROM history
+ORDER BY id DESC
+LIMIT ?;
+
+-- name: GetTopChapters :many
+SELECT book, chapter, COUNT(*) AS count FROM history_chapters
+GROUP BY book, chapter
+ORDER BY count DESC, book, chapter
+LIMIT ?;
+
+-- name: ClearHistory :exec
+DELETE FROM history;
+
+-- name: ClearHistoryChapters :exec
+DELETE FROM history_chapters;
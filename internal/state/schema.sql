@@ -0,0 +1,36 @@
+CREATE TABLE IF NOT EXISTS plans (
+        name TEXT NOT NULL,
+        start TEXT NOT NULL,
+        paused_at TEXT NOT NULL DEFAULT '',
+        paused_days INTEGER NOT NULL DEFAULT 0,
+        PRIMARY KEY (name));
+CREATE TABLE IF NOT EXISTS plan_days (
+        plan TEXT NOT NULL,
+        day INTEGER NOT NULL,
+        done_at TEXT NOT NULL,
+        PRIMARY KEY (plan, day));
+CREATE TABLE IF NOT EXISTS annotations (
+        id INTEGER NOT NULL,
+        kind TEXT NOT NULL,
+        from_book INTEGER NOT NULL,
+        from_chapter INTEGER NOT NULL,
+        from_verse INTEGER NOT NULL,
+        to_book INTEGER NOT NULL,
+        to_chapter INTEGER NOT NULL,
+        to_verse INTEGER NOT NULL,
+        value TEXT NOT NULL DEFAULT '',
+        created_at TEXT NOT NULL,
+        PRIMARY KEY (id));
+CREATE INDEX IF NOT EXISTS annotations_book ON annotations (from_book, to_book);
+CREATE TABLE IF NOT EXISTS history (
+        id INTEGER NOT NULL,
+        query TEXT NOT NULL,
+        reference TEXT NOT NULL,
+        translation TEXT NOT NULL,
+        created_at TEXT NOT NULL,
+        PRIMARY KEY (id));
+CREATE TABLE IF NOT EXISTS history_chapters (
+        history INTEGER NOT NULL,
+        book INTEGER NOT NULL,
+        chapter INTEGER NOT NULL,
+        PRIMARY KEY (history, book, chapter));
@@ -0,0 +1,45 @@
+package state
+
+import (
+	"context"
+	"database/sql"
+	_ "embed"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// name of the database in the state directory
+const STATE_FILE = "state.db"
+
+// kinds of annotations. Value of a note is its text, of a highlight the
+// style and of a tag its name. Bookmarks have no value
+const (
+	KIND_NOTE      = "note"
+	KIND_HIGHLIGHT = "highlight"
+	KIND_TAG       = "tag"
+	KIND_BOOKMARK  = "bookmark"
+)
+
+//go:embed schema.sql
+var schema string
+
+// Open opens the database of the user at path, creating it and its
+// tables when they are not there yet
+func Open(ctx context.Context, path string) (*sql.DB, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, fmt.Errorf("state database: %w", err)
+	}
+
+	conn, err := sql.Open("sqlite", path)
+	if err != nil {
+		return nil, fmt.Errorf("state database: %w", err)
+	}
+
+	if _, err := conn.ExecContext(ctx, schema); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("state database %s: %w", path, err)
+	}
+
+	return conn, nil
+}
@@ -0,0 +1,248 @@
+package bible
+
+import (
+	"fmt"
+	"io"
+	"iter"
+	"math"
+	"strings"
+
+	"github.com/ButbkaDrug/bible/internal/repository"
+)
+
+// SeqRenderer is a renderer that prints verses while they are read, so
+// the whole Bible never has to be in memory
+type SeqRenderer interface {
+	Renderer
+	RenderSeq(io.Writer, iter.Seq2[Verse, error]) error
+}
+
+// Verses streams verses of the passage in order. The query runs when
+// the sequence is ranged over, and stops when the loop breaks
+func (app *Bible) Verses(p Passage) iter.Seq2[Verse, error] {
+	sp := newSpan(p)
+
+	return app.between(sp.lo, sp.hi)
+}
+
+// All streams every verse of the module in order
+func (app *Bible) All() iter.Seq2[Verse, error] {
+	return app.between(
+		Reference{BookNumber: 0, Chapter: 0, Verse: 0},
+		Reference{BookNumber: math.MaxInt, Chapter: math.MaxInt, Verse: math.MaxInt},
+	)
+}
+
+// SearchIter streams verses that Search would return. Books outside of
+// the canon are skipped, but the order is the one of book numbers
+func (app *Bible) SearchIter(s string) iter.Seq2[Verse, error] {
+	query := strings.Trim(s, " \n\r\t")
+	query = strings.ReplaceAll(query, " ", "%")
+	query = fmt.Sprintf("%%%s%%", query)
+
+	verses := app.wrapSeq(app.db.IterSearch(app.ctx, query))
+
+	return func(yield func(Verse, error) bool) {
+		for v, err := range verses {
+			if err == nil && !app.canon.Has(v.BookNumber) {
+				continue
+			}
+
+			if !yield(v, err) {
+				return
+			}
+		}
+	}
+}
+
+func (app *Bible) between(lo, hi Reference) iter.Seq2[Verse, error] {
+	return app.wrapSeq(app.db.IterVersesBetween(app.ctx, repository.GetVersesBetweenParams{
+		FromBook:    float64(lo.BookNumber),
+		FromChapter: float64(lo.Chapter),
+		FromVerse:   float64(lo.Verse),
+		ToBook:      float64(hi.BookNumber),
+		ToChapter:   float64(hi.Chapter),
+		ToVerse:     float64(hi.Verse),
+	}))
+}
+
+func (app *Bible) wrapSeq(rows iter.Seq2[repository.Verse, error]) iter.Seq2[Verse, error] {
+	return func(yield func(Verse, error) bool) {
+		for row, err := range rows {
+			if err != nil {
+				yield(Verse{}, err)
+				return
+			}
+
+			if !yield(app.wrapVerse(row), nil) {
+				return
+			}
+		}
+	}
+}
+
+// RenderSeq prints verses as they come when the renderer can do that,
+// other renderers get them all at once
+func (app *Bible) RenderSeq(verses iter.Seq2[Verse, error]) error {
+	if r, ok := app.render.(SeqRenderer); ok {
+		return r.RenderSeq(app.writer, verses)
+	}
+
+	var all []Verse
+
+	for v, err := range verses {
+		if err != nil {
+			return err
+		}
+		all = append(all, v)
+	}
+
+	return app.render.Render(app.writer, all)
+}
+
+// passages of the query when it is read as a stream: a whole book or a
+// range that goes over books. Context needs verses around the passage,
+// so queries with context are read at once
+func (app *Bible) streamed() ([]Passage, bool) {
+	if app.before > 0 || app.after > 0 {
+		return nil, false
+	}
+
+	request, err := Parse(app.query)
+	if err != nil {
+		return nil, false
+	}
+
+	switch r := request.(type) {
+	case ConcreteRequest:
+		number, err := app.nameOnlyBook(r.ref.book)
+		if !app.wholeBooks || err != nil {
+			return nil, false
+		}
+
+		return []Passage{NewPassage(Reference{BookNumber: number})}, true
+	case RangeRequest:
+		passages, err := app.Passages(r)
+		if err != nil || len(passages) != 1 || passages[0].Start.BookNumber == passages[0].End.BookNumber {
+			return nil, false
+		}
+
+		return passages, true
+	}
+
+	return nil, false
+}
+
+// streams verses of the passages the way Execute reads them, one book
+// at a time. Headings and annotations of a book are read before its
+// verses, so no other query runs while the verses are read
+func (app *Bible) readSeq(passages []Passage) iter.Seq2[Verse, error] {
+	return func(yield func(Verse, error) bool) {
+		books, err := app.db.GetBookNames(app.ctx)
+		if err != nil {
+			yield(Verse{}, err)
+			return
+		}
+
+		// books the passages name are read even when they are outside of
+		// the canon
+		var named = make(map[int]bool)
+		for _, p := range passages {
+			named[p.Start.BookNumber] = true
+			named[p.End.BookNumber] = true
+		}
+
+		// first verse of every chapter, for the lookup
+		var chapters []Verse
+		var count int
+
+	spans:
+		for _, sp := range NewPassageSet(passages...).spans {
+			for _, b := range books {
+				number := int(b.BookNumber)
+
+				if number < sp.lo.BookNumber || number > sp.hi.BookNumber {
+					continue
+				}
+
+				if !named[number] && !app.canon.Has(number) {
+					continue
+				}
+
+				headings, err := app.bookHeadings(number)
+				if err != nil {
+					yield(Verse{}, err)
+					return
+				}
+
+				var annotations []Annotation
+				if app.annotations != nil {
+					if annotations, err = app.annotations.BookAnnotations(app.ctx, number); err != nil {
+						yield(Verse{}, err)
+						return
+					}
+				}
+
+				lo := maxRef(sp.lo, Reference{BookNumber: number})
+				hi := minRef(sp.hi, Reference{BookNumber: number, Chapter: math.MaxInt, Verse: math.MaxInt})
+
+				for v, err := range app.between(lo, hi) {
+					if err != nil {
+						yield(Verse{}, err)
+						return
+					}
+
+					if app.limit > 0 && count >= app.limit {
+						break spans
+					}
+					count++
+
+					v.Heading = headings[[2]int{v.Chapter, v.Verse}]
+					addAnnotations(&v, annotations)
+
+					if n := len(chapters); n == 0 || chapters[n-1].BookNumber != v.BookNumber || chapters[n-1].Chapter != v.Chapter {
+						chapters = append(chapters, v)
+					}
+
+					if !yield(v, nil) {
+						return
+					}
+				}
+			}
+		}
+
+		if count < 1 {
+			yield(Verse{}, app.notFound(passages))
+			return
+		}
+
+		if app.onLookup != nil {
+			app.onLookup(newLookup(app.query, passages, chapters))
+		}
+	}
+}
+
+// headings of the book by chapter and verse, when they are turned on
+func (app *Bible) bookHeadings(book int) (map[[2]int]string, error) {
+	if !app.headings {
+		return nil, nil
+	}
+
+	stories, err := app.db.GetBookStories(app.ctx, float64(book))
+	if err != nil {
+		return nil, err
+	}
+
+	var result = make(map[[2]int]string)
+
+	for _, story := range stories {
+		key := [2]int{int(story.Chapter), int(story.Verse)}
+
+		if result[key] != "" {
+			result[key] += "\n"
+		}
+		result[key] += story.Title
+	}
+
+	return result, nil
+}
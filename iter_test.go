@@ -0,0 +1,182 @@
+package bible
+
+import (
+	"bytes"
+	"fmt"
+	"iter"
+	"strings"
+	"testing"
+)
+
+func collect(t *testing.T, seq iter.Seq2[Verse, error]) string {
+	t.Helper()
+
+	var refs []string
+
+	for v, err := range seq {
+		if err != nil {
+			t.Fatalf("iteration failed: %s", err)
+		}
+		refs = append(refs, fmt.Sprintf("%s %d:%d", v.Book, v.Chapter, v.Verse))
+	}
+
+	return strings.Join(refs, ", ")
+}
+
+func TestVerses(t *testing.T) {
+	app := testModule(t)
+
+	tests := []string{
+		"John 20:2-21:1",
+		"John 21",
+		"Acts",
+		"John 22",
+	}
+
+	expectedResults := []string{
+		"John 20:2, John 20:3, John 21:1",
+		"John 21:1, John 21:2",
+		"Acts 1:1, Acts 1:2, Acts 1:3",
+		"",
+	}
+
+	for i, test := range tests {
+		passages, err := app.Resolve(test)
+		if err != nil {
+			t.Fatalf("TEST[%d] failed: %s", i, err)
+		}
+
+		if result := collect(t, app.Verses(passages[0])); result != expectedResults[i] {
+			t.Fatalf("TEST[%d] failed: expected %q got %q", i, expectedResults[i], result)
+		}
+	}
+}
+
+func TestAllAndSearchIter(t *testing.T) {
+	app := testModule(t)
+
+	if result := collect(t, app.All()); strings.Count(result, ",") != 7 {
+		t.Fatalf("TEST[0] failed: expected 8 verses got %q", result)
+	}
+
+	if result := collect(t, app.SearchIter("nothing like it")); result != "" {
+		t.Fatalf("TEST[1] failed: expected nothing got %q", result)
+	}
+
+	// the loop can stop early
+	var count int
+	for range app.SearchIter("text") {
+		if count++; count == 2 {
+			break
+		}
+	}
+
+	if count != 2 {
+		t.Fatalf("TEST[2] failed: expected 2 verses got %d", count)
+	}
+}
+
+func TestRenderSeq(t *testing.T) {
+	app := testModule(t)
+
+	renderers := []SeqRenderer{
+		NewDefaultRender().SetWidth(40),
+		NewJSONRender(),
+	}
+
+	verses, err := app.Search("text")
+	if err != nil {
+		t.Fatalf("search failed: %s", err)
+	}
+
+	for i, r := range renderers {
+		var expected, result bytes.Buffer
+
+		if err := r.Render(&expected, verses); err != nil {
+			t.Fatalf("TEST[%d] failed: %s", i, err)
+		}
+
+		if err := r.RenderSeq(&result, app.SearchIter("text")); err != nil {
+			t.Fatalf("TEST[%d] failed: %s", i, err)
+		}
+
+		if result.String() != expected.String() {
+			t.Fatalf("TEST[%d] failed: expected\n%s\ngot\n%s", i, expected.String(), result.String())
+		}
+	}
+}
+
+func TestRunStreams(t *testing.T) {
+	app := testModule(t,
+		`INSERT INTO stories VALUES (510, 1, 1, 0, 'Prologue')`,
+		`INSERT INTO stories VALUES (510, 1, 1, 1, 'Promise')`,
+	)
+
+	app.SetHeadings(true).SetAnnotations(testAnnotations{
+		{Passage: Passage{Start: Reference{500, 21, 2}, End: Reference{510, 1, 1}}, Highlight: "yellow", Note: "note"},
+	})
+
+	var lookups []Lookup
+	app.SetOnLookup(func(l Lookup) {
+		lookups = append(lookups, l)
+	})
+
+	tests := []struct {
+		query string
+		limit int
+	}{
+		{"Acts", 0},
+		{"John 21:2-Acts 1:2", 0},
+		// the limit stops before Acts
+		{"John-Acts", 4},
+	}
+
+	expectedResults := []string{
+		"Acts [Acts 1]",
+		"John 21:2-Acts 1:2 [John 21 Acts 1]",
+		"John-Acts [John 20 John 21]",
+	}
+
+	for i, test := range tests {
+		app.SetWholeBooks(true).SetLimit(test.limit).SetQuery(test.query)
+
+		if _, ok := app.streamed(); !ok {
+			t.Fatalf("TEST[%d] failed: %q is not streamed", i, test.query)
+		}
+
+		lookups = nil
+		verses, err := app.Execute()
+		if err != nil {
+			t.Fatalf("TEST[%d] failed: %s", i, err)
+		}
+
+		var expected, result bytes.Buffer
+		if err := NewJSONRender().Render(&expected, verses); err != nil {
+			t.Fatalf("TEST[%d] failed: %s", i, err)
+		}
+
+		lookups = nil
+		if err := app.SetRender(NewJSONRender()).SetWriter(&result).Run(); err != nil {
+			t.Fatalf("TEST[%d] failed: %s", i, err)
+		}
+
+		if result.String() != expected.String() {
+			t.Fatalf("TEST[%d] failed: expected\n%s\ngot\n%s", i, expected.String(), result.String())
+		}
+
+		// the limit stops the last one before Acts
+		annotated := strings.Contains(result.String(), `"heading": "Prologue\nPromise"`) && strings.Contains(result.String(), `"highlight": "yellow"`)
+		if annotated != (test.limit == 0) {
+			t.Fatalf("TEST[%d] failed: expected headings and annotations, got\n%s", i, result.String())
+		}
+
+		if len(lookups) != 1 || fmt.Sprintf("%s %v", lookups[0].Reference(), lookups[0].Chapters) != expectedResults[i] {
+			t.Fatalf("TEST[%d] failed: expected lookup %q, got %v", i, expectedResults[i], lookups)
+		}
+	}
+
+	// single chapters and verses are read at once
+	if _, ok := app.SetQuery("John 21:1-2").streamed(); ok {
+		t.Fatalf("TEST[%d] failed: a chapter is streamed", len(tests))
+	}
+}
@@ -0,0 +1,102 @@
+package bible
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+)
+
+type jsonVerse struct {
+	Book       string   `json:"book"`
+	BookNumber int      `json:"book_number"`
+	Chapter    int      `json:"chapter"`
+	Verse      int      `json:"verse"`
+	Heading    string   `json:"heading,omitempty"`
+	Text       string   `json:"text"`
+	Context    bool     `json:"context,omitempty"`
+	Highlight  string   `json:"highlight,omitempty"`
+	Notes      []string `json:"notes,omitempty"`
+}
+
+// renders verses as JSON array with markup removed from the text.
+// Useful for scripts and editor plugins.
+type jsonRender struct {
+	director *lineDirector
+}
+
+func NewJSONRender() *jsonRender {
+	return &jsonRender{
+		director: NewLineDirector(),
+	}
+}
+
+func (j *jsonRender) SetHighlights([]string) Renderer {
+	return j
+}
+
+func (j *jsonRender) Render(w io.Writer, verses []Verse) error {
+	if len(verses) < 1 {
+		return errors.New("JSON RENDERER: no verses to print")
+	}
+
+	var out = make([]jsonVerse, len(verses))
+
+	for i, v := range verses {
+		out[i] = j.verse(v)
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+
+	return encoder.Encode(out)
+}
+
+// RenderSeq writes the same array as Render, one verse at a time
+func (j *jsonRender) RenderSeq(w io.Writer, verses iter.Seq2[Verse, error]) error {
+	var count int
+
+	for v, err := range verses {
+		if err != nil {
+			return err
+		}
+
+		data, err := json.MarshalIndent(j.verse(v), "  ", "  ")
+		if err != nil {
+			return err
+		}
+
+		sep := ",\n  "
+		if count == 0 {
+			sep = "[\n  "
+		}
+		count++
+
+		if _, err := fmt.Fprintf(w, "%s%s", sep, data); err != nil {
+			return err
+		}
+	}
+
+	if count == 0 {
+		return errors.New("JSON RENDERER: no verses to print")
+	}
+
+	_, err := io.WriteString(w, "\n]\n")
+
+	return err
+}
+
+func (j *jsonRender) verse(v Verse) jsonVerse {
+	return jsonVerse{
+		Book:       v.Book,
+		BookNumber: v.BookNumber,
+		Chapter:    v.Chapter,
+		Verse:      v.Verse,
+		Heading:    v.Heading,
+		Text:       j.director.CreateBareLine(NewLineBuilder(v)),
+		Context:    v.Context,
+		Highlight:  v.Highlight,
+		Notes:      v.Notes,
+	}
+}
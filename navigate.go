@@ -0,0 +1,131 @@
+package bible
+
+import (
+	"database/sql"
+	"errors"
+
+	"github.com/ButbkaDrug/bible/internal/repository"
+)
+
+var (
+	ErrStartOfBible = errors.New("there is nothing before the first verse")
+	ErrEndOfBible   = errors.New("there is nothing after the last verse")
+)
+
+// Next returns the verse that follows v. At the end of the chapter it
+// moves to the next chapter and at the end of the book to the next book
+func (app *Bible) Next(v Verse) (Verse, error) {
+	return app.Offset(v, 1)
+}
+
+// Prev returns the verse that goes before v
+func (app *Bible) Prev(v Verse) (Verse, error) {
+	return app.Offset(v, -1)
+}
+
+// Offset moves n verses forward, or backward when n is negative. Only
+// book number, chapter and verse of v are used. Chapter ends and book
+// order come from the module, so missing verses are skipped and
+// John 21:25 + 1 is Acts 1:1.
+func (app *Bible) Offset(v Verse, n int) (Verse, error) {
+	var row repository.Verse
+	var err error
+
+	switch {
+	case n > 0:
+		row, err = app.db.GetVerseAfter(app.ctx, repository.GetVerseAfterParams{
+			BookNumber: float64(v.BookNumber),
+			Chapter:    float64(v.Chapter),
+			Verse:      float64(v.Verse),
+			Offset:     int64(n - 1),
+		})
+		if errors.Is(err, sql.ErrNoRows) {
+			return Verse{}, ErrEndOfBible
+		}
+	case n < 0:
+		row, err = app.db.GetVerseBefore(app.ctx, repository.GetVerseBeforeParams{
+			BookNumber: float64(v.BookNumber),
+			Chapter:    float64(v.Chapter),
+			Verse:      float64(v.Verse),
+			Offset:     int64(-n - 1),
+		})
+		if errors.Is(err, sql.ErrNoRows) {
+			return Verse{}, ErrStartOfBible
+		}
+	default:
+		return app.verse(v)
+	}
+
+	if err != nil {
+		return Verse{}, err
+	}
+
+	return app.wrapVerse(row), nil
+}
+
+// NextChapter returns the first verse of the chapter after the one v
+// is in
+func (app *Bible) NextChapter(v Verse) (Verse, error) {
+	v.Verse = int(MAX_VERSE)
+	return app.Offset(v, 1)
+}
+
+// PrevChapter returns the first verse of the chapter before the one v
+// is in
+func (app *Bible) PrevChapter(v Verse) (Verse, error) {
+	v.Verse = 0
+
+	last, err := app.Offset(v, -1)
+	if err != nil {
+		return Verse{}, err
+	}
+
+	last.Verse = 0
+
+	return app.Offset(last, 1)
+}
+
+// Between returns all the verses from one to the other, both included.
+// The range can span chapters and books
+func (app *Bible) Between(from, to Verse) ([]Verse, error) {
+	rows, err := app.db.GetVersesBetween(app.ctx, repository.GetVersesBetweenParams{
+		FromBook:    float64(from.BookNumber),
+		FromChapter: float64(from.Chapter),
+		FromVerse:   float64(from.Verse),
+		ToBook:      float64(to.BookNumber),
+		ToChapter:   float64(to.Chapter),
+		ToVerse:     float64(to.Verse),
+	})
+	if err != nil {
+		return []Verse{}, err
+	}
+
+	var result = make([]Verse, len(rows))
+
+	for i, row := range rows {
+		result[i] = app.wrapVerse(row)
+	}
+
+	return result, nil
+}
+
+func (app *Bible) verse(v Verse) (Verse, error) {
+	rows, err := app.db.GetVersesCollection(app.ctx, repository.GetVersesCollectionParams{
+		BookNumber: float64(v.BookNumber),
+		Chapter:    float64(v.Chapter),
+		Verse:      float64(v.Verse),
+	})
+	if err != nil {
+		return Verse{}, err
+	}
+
+	if len(rows) < 1 {
+		return Verse{}, errors.New("verse not found")
+	}
+
+	return app.wrapVerse(rows[0]), nil
+}
+
+func (app *Bible) wrapVerse(v repository.Verse) Verse {
+	return wrapVerses(app.getBookName(v.BookNumber), []repository.Verse{v})[0]
+}
@@ -0,0 +1,155 @@
+package bible
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"os"
+	"testing"
+
+	_ "modernc.org/sqlite"
+)
+
+// will create a module with John 20-21 and Acts 1, three verses in
+// every chapter but the last one of John, which has only two. Extra
+// statements run after it is filled
+func testModule(t *testing.T, extra ...string) *Bible {
+	t.Helper()
+
+	conn, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatalf("failed to open database: %s", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	schema, err := os.ReadFile("schema.sql")
+	if err != nil {
+		t.Fatalf("failed to read schema: %s", err)
+	}
+
+	if _, err := conn.Exec(string(schema)); err != nil {
+		t.Fatalf("failed to create tables: %s", err)
+	}
+
+	statements := []string{
+		`INSERT INTO books VALUES (500, 'Jn', 'John', '#ff6600')`,
+		`INSERT INTO books VALUES (510, 'Acts', 'Acts', '#00ff00')`,
+	}
+
+	for _, v := range [][3]int{
+		{500, 20, 1}, {500, 20, 2}, {500, 20, 3},
+		{500, 21, 1}, {500, 21, 2},
+		{510, 1, 1}, {510, 1, 2}, {510, 1, 3},
+	} {
+		statements = append(statements, fmt.Sprintf(
+			`INSERT INTO verses VALUES (%d, %d, %d, 'text')`, v[0], v[1], v[2],
+		))
+	}
+
+	for _, s := range append(statements, extra...) {
+		if _, err := conn.Exec(s); err != nil {
+			t.Fatalf("failed to fill the module: %s", err)
+		}
+	}
+
+	return New(context.Background(), conn, "plain")
+}
+
+func TestOffset(t *testing.T) {
+	app := testModule(t)
+
+	tests := []struct {
+		from Verse
+		n    int
+	}{
+		{from: Verse{BookNumber: 500, Chapter: 20, Verse: 1}, n: 1},
+		{from: Verse{BookNumber: 500, Chapter: 20, Verse: 3}, n: 1},
+		{from: Verse{BookNumber: 500, Chapter: 21, Verse: 2}, n: 1},
+		{from: Verse{BookNumber: 500, Chapter: 21, Verse: 2}, n: 3},
+		{from: Verse{BookNumber: 510, Chapter: 1, Verse: 1}, n: -1},
+		{from: Verse{BookNumber: 510, Chapter: 1, Verse: 3}, n: -6},
+		{from: Verse{BookNumber: 500, Chapter: 21, Verse: 1}, n: 0},
+	}
+
+	expectedResults := []string{
+		"John 20:2",
+		"John 21:1",
+		"Acts 1:1",
+		"Acts 1:3",
+		"John 21:2",
+		"John 20:2",
+		"John 21:1",
+	}
+
+	for i, test := range tests {
+		v, err := app.Offset(test.from, test.n)
+		if err != nil {
+			t.Fatalf("TEST[%d] failed: %s", i, err)
+		}
+
+		result := fmt.Sprintf("%s %d:%d", v.Book, v.Chapter, v.Verse)
+		if result != expectedResults[i] {
+			t.Fatalf("TEST[%d] failed: expected %s got %s", i, expectedResults[i], result)
+		}
+	}
+
+	if _, err := app.Next(Verse{BookNumber: 510, Chapter: 1, Verse: 3}); err != ErrEndOfBible {
+		t.Fatalf("expected end of bible got %v", err)
+	}
+
+	if _, err := app.Prev(Verse{BookNumber: 500, Chapter: 20, Verse: 1}); err != ErrStartOfBible {
+		t.Fatalf("expected start of bible got %v", err)
+	}
+}
+
+func TestChapterNavigation(t *testing.T) {
+	app := testModule(t)
+
+	next, err := app.NextChapter(Verse{BookNumber: 500, Chapter: 21, Verse: 1})
+	if err != nil || next.BookNumber != 510 || next.Chapter != 1 || next.Verse != 1 {
+		t.Fatalf("expected Acts 1:1 got %+v %v", next, err)
+	}
+
+	prev, err := app.PrevChapter(Verse{BookNumber: 510, Chapter: 1, Verse: 3})
+	if err != nil || prev.BookNumber != 500 || prev.Chapter != 21 || prev.Verse != 1 {
+		t.Fatalf("expected John 21:1 got %+v %v", prev, err)
+	}
+
+	if _, err := app.PrevChapter(Verse{BookNumber: 500, Chapter: 20, Verse: 2}); err != ErrStartOfBible {
+		t.Fatalf("expected start of bible got %v", err)
+	}
+
+	verses, err := app.Between(
+		Verse{BookNumber: 500, Chapter: 21, Verse: 2},
+		Verse{BookNumber: 510, Chapter: 1, Verse: 2},
+	)
+	if err != nil {
+		t.Fatalf("failed to get verses: %s", err)
+	}
+
+	if len(verses) != 3 || verses[0].Book != "John" || verses[2].Book != "Acts" {
+		t.Fatalf("expected John 21:2 to Acts 1:2 got %+v", verses)
+	}
+}
+
+func TestAddContext(t *testing.T) {
+	app := testModule(t).SetContextVerses(1, 2)
+
+	verses, err := app.AddContext([]Verse{
+		{BookNumber: 500, Chapter: 20, Verse: 1},
+		{BookNumber: 500, Chapter: 20, Verse: 3},
+	})
+	if err != nil {
+		t.Fatalf("failed to add context: %s", err)
+	}
+
+	var result []string
+	for _, v := range verses {
+		result = append(result, fmt.Sprintf("%d:%d %v", v.Chapter, v.Verse, v.Context))
+	}
+
+	expect := "[20:1 false 20:2 true 20:3 false 21:1 true 21:2 true]"
+	if fmt.Sprint(result) != expect {
+		t.Fatalf("expected %s got %s", expect, result)
+	}
+}
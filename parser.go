@@ -3,9 +3,11 @@ package bible
 import (
 	"errors"
 	"fmt"
+	"regexp"
 	"strconv"
 	"strings"
 	"unicode"
+	"unicode/utf8"
 )
 
 type RequestType int
@@ -77,12 +79,98 @@ func (r referance) Book() string     { return r.book }
 func (r referance) Chapter() float64 { return r.chapter }
 func (r referance) Verse() float64   { return r.verse }
 
+// ParseError points at the place where the query stopped being a
+// reference
+type ParseError struct {
+	// query as the user wrote it, before `II`, `3.16` and the like were
+	// rewritten for the parser
+	Query string
+	// byte offset of the problem in Query
+	Offset int
+	// what was found at Offset, empty at the end of the query
+	Token string
+	Msg   string
+	// book name the query starts with, if any
+	Book string
+}
+
+func (e *ParseError) Error() string {
+	if e.Token == "" {
+		return fmt.Sprintf("%s at col %d", e.Msg, e.Col())
+	}
+
+	return fmt.Sprintf("%s, got `%s` at col %d", e.Msg, e.Token, e.Col())
+}
+
+// Col is the position of the problem in characters, starting at 1
+func (e *ParseError) Col() int {
+	return utf8.RuneCountInString(e.Query[:min(e.Offset, len(e.Query))]) + 1
+}
+
+// will make the offset of errors from a part of the query relative to
+// the whole of it
+func shiftError(err error, offset int) error {
+	var pe *ParseError
+	if errors.As(err, &pe) {
+		pe.Offset += offset
+	}
+
+	return err
+}
+
+// error at the start of s, which begins at offset of the part
+func errorAt(part string, offset int, msg string) *ParseError {
+	rest := skipWhitespace(part[offset:])
+
+	return &ParseError{
+		Offset: len(part) - len(rest),
+		Token:  firstToken(rest),
+		Msg:    msg,
+	}
+}
+
+// word, number or a single character at the start of s
+func firstToken(s string) string {
+	for i, r := range s {
+		if i == 0 {
+			if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+				return string(r)
+			}
+			continue
+		}
+
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '.' {
+			return s[:i]
+		}
+	}
+
+	return s
+}
+
 func Parse(s string) (Request, error) {
 
 	if s == "" {
 		return EmptyRequest{}, nil
 	}
 
+	r := rewriteNotation(rewriteOrdinals(newRewritten(s)))
+
+	request, err := parse(r.text)
+
+	var pe *ParseError
+	if errors.As(err, &pe) {
+		pe.Query = s
+		pe.Offset = r.originOf(pe.Offset)
+
+		if isName(r.text) {
+			pe.Book, _ = parseName(r.text)
+		}
+	}
+
+	return request, err
+}
+
+func parse(s string) (Request, error) {
 	switch readRequestType(s) {
 	case CONCRETE:
 		return parseConcreteRequest(s)
@@ -94,7 +182,7 @@ func Parse(s string) (Request, error) {
 		return parseMixedRequest(s)
 	}
 
-	return nil, nil
+	return nil, errorAt(s, 0, "expected reference")
 }
 
 func parseConcreteRequest(s string) (ConcreteRequest, error) {
@@ -105,19 +193,55 @@ func parseConcreteRequest(s string) (ConcreteRequest, error) {
 	}, nil
 }
 
+// end of a range that goes to the last verse of the chapter, `3:16-end`,
+// or to the last chapter of the book, `3-end`
+const RANGE_END = "end"
+
 // parses range expression will split on dash and parse
 // two sides of the request separately
 func parseRangeRequest(s string) (RangeRequest, error) {
 	var r RangeRequest
 
 	left, right, _ := strings.Cut(s, "-")
+	rightAt := len(left) + 1
 
-	start := parseGenericRequest(left)
-	end := parseGenericRequest(right)
+	start, err := readReference(left)
+	if err != nil {
+		return RangeRequest{Start: start, End: referance{book: start.book}}, err
+	}
 
-	if end.chapter == 0 && end.verse == 0 {
-		end.chapter = start.chapter
-		end.verse = start.verse
+	if strings.EqualFold(strings.TrimSpace(right), RANGE_END) {
+		// chapter 0 is the end of the book, `3ff` are chapters
+		r.Start = start
+		r.End = referance{book: start.book}
+
+		if start.verse > 0 {
+			r.End.chapter = start.chapter
+		}
+
+		return r, nil
+	}
+
+	end, err := readReference(right)
+	if err == nil && strings.TrimSpace(right) == "" {
+		err = errorAt(right, 0, "expected end of range after '-'")
+	}
+
+	// `Gal-Col` are whole books
+	if err == nil && start.book != "" && start.chapter == 0 && end.book != "" && end.chapter == 0 {
+		r.Start = start
+		r.End = end
+
+		return r, nil
+	}
+
+	// `John 3-C`, the end needs numbers even when it has a book
+	if err == nil && end.book != "" && end.chapter == 0 {
+		err = errorAt(right, len(right), "expected chapter number after book name")
+	}
+
+	if err != nil {
+		return RangeRequest{Start: start, End: referance{book: start.book}}, shiftError(err, rightAt)
 	}
 
 	if end.book == "" {
@@ -133,20 +257,20 @@ func parseRangeRequest(s string) (RangeRequest, error) {
 		end.chapter = start.chapter
 	}
 
-	if end.verse == 0 {
-		end.verse = MAX_VERSE
+	// ranges over books are checked when book numbers are known
+	if !strings.EqualFold(start.book, end.book) {
+		r.Start = start
+		r.End = end
+
+		return r, nil
 	}
 
 	if end.chapter < start.chapter {
-		msg := "END chapter cannot be smaller then START"
-		err := fmt.Sprintf("ERROR: %s. start: %#v end: %#v", msg, start, end)
-		return r, errors.New(err)
+		return r, shiftError(errorAt(right, 0, "end chapter of the range is before the start"), rightAt)
 	}
 
 	if start.chapter == end.chapter && start.verse > end.verse {
-		msg := "END verse cannot be smaller then START verse within the same chapter"
-		err := fmt.Sprintf("ERROR: %s. start: %#v end: %#v", msg, start, end)
-		return r, errors.New(err)
+		return r, shiftError(errorAt(right, 0, "end verse of the range is before the start"), rightAt)
 	}
 
 	r.Start = start
@@ -155,35 +279,254 @@ func parseRangeRequest(s string) (RangeRequest, error) {
 	return r, nil
 }
 
+// reads reference and ignores errors, what could not be read is 0
 func parseGenericRequest(s string) referance {
-	//typical case is (NUMBER?)NAME CHAPTER:VERSE
-	// but NAME -- we will ignore this case for now
-	// or CHPATER:VERSE
-	// or CHAPTER?VERSE
+	r, _ := readReference(s)
+	return r
+}
+
+// reads `(NUMBER?)NAME CHAPTER:VERSE`, `CHAPTER:VERSE`, `CHAPTER` or
+// only the name. Offsets of errors are relative to s
+func readReference(s string) (referance, error) {
 	var r referance
-	var name string
+	var rest = s
 
 	if isName(s) {
-		name, s = parseName(s)
+		r.book, rest = parseName(s)
 	}
 
-	r.book = name
+	rest = skipWhitespace(rest)
+	at := len(s) - len(rest)
 
-	left, right, found := strings.Cut(s, ":")
+	if strings.TrimSpace(rest) == "" {
+		return r, nil
+	}
 
-	leftNum, _ := readNumber(left)
+	left, right, found := strings.Cut(rest, ":")
 
-	r.chapter = float64(leftNum)
+	chapter, err := readPart(s, at, left, "expected chapter number")
+	if err != nil {
+		return r, err
+	}
+
+	r.chapter = float64(chapter)
 
 	if !found {
-		return r
+		return r, nil
 	}
 
-	rightNum, _ := readNumber(right)
+	verse, err := readPart(s, at+len(left)+1, right, "expected verse number after ':'")
+	if err != nil {
+		return r, err
+	}
 
-	r.verse = float64(rightNum)
+	r.verse = float64(verse)
 
-	return r
+	return r, nil
+}
+
+// reads number that has to fill the part of s starting at offset
+func readPart(s string, offset int, part string, msg string) (int, error) {
+	number, rest := readNumber(part)
+
+	if rest == skipWhitespace(part) {
+		return 0, errorAt(s, offset, msg)
+	}
+
+	if number < 0 {
+		return 0, errorAt(s, offset, "number is too big")
+	}
+
+	if strings.TrimSpace(rest) != "" {
+		return number, errorAt(s, offset+len(part)-len(rest), "unexpected text after number")
+	}
+
+	return number, nil
+}
+
+// ordinal prefixes of book names as they are printed, e.g. `II Cor`,
+// `First Thessalonians`, `2nd Kings` or `1-е Коринфянам`. Dash of the
+// Russian form would be read as a range, so they are replaced before
+// anything else is parsed. Ordinals are only replaced before a word that
+// begins a numbered book, so `Am I my brother` stays words
+var ordinals = regexp.MustCompile(`(?i)(^|[\s,;(])(` +
+	`iv|iii|ii|i|first|second|third|fourth|` +
+	`[1-4](?:st|nd|rd|th)|[1-4]-(?:е|я|ое|ая|ой|ше|а|ге)|` +
+	`перв(?:ое|ая|ой)|втор(?:ое|ая|ой)|треть(?:е|я)|четв[её]рт(?:ое|ая)|` +
+	`перш(?:е|а)|друг(?:е|а)|трет(?:є|я)|четверт(?:е|а)` +
+	`)\.?\s+(\pL+)`)
+
+var ordinalNumbers = map[string]string{
+	"i": "1", "ii": "2", "iii": "3", "iv": "4",
+	"first": "1", "second": "2", "third": "3", "fourth": "4",
+	"перв": "1", "втор": "2", "трет": "3", "четв": "4",
+	"перш": "1", "друг": "2",
+}
+
+// will replace ordinal prefixes of book names with numbers
+func normalizeOrdinals(s string) string {
+	return rewriteOrdinals(newRewritten(s)).text
+}
+
+func rewriteOrdinals(r rewritten) rewritten {
+	return r.replace(ordinals, func(m []string) string {
+		prefix, ordinal, word := m[1], strings.ToLower(m[2]), m[3]
+
+		number, ok := ordinalNumbers[ordinal]
+		if !ok && ordinal[0] >= '1' && ordinal[0] <= '4' {
+			number, ok = ordinal[:1], true
+		}
+
+		for w, n := range ordinalNumbers {
+			if !ok && strings.HasPrefix(ordinal, w) {
+				number, ok = n, true
+			}
+		}
+
+		if !numberedBook(number, word) {
+			return m[0]
+		}
+
+		return prefix + number + " " + word
+	})
+}
+
+// reports whether the number and the word are a name of a book, or the
+// beginning of one, e.g. `1 John` or `2 Thess`
+func numberedBook(number, word string) bool {
+	key := aliasKey(number + word)
+
+	if _, ok := defAliases[key]; ok {
+		return true
+	}
+
+	if utf8.RuneCountInString(word) < MIN_PREFIX {
+		return false
+	}
+
+	for k := range defAliases {
+		if strings.HasPrefix(k, key) {
+			return true
+		}
+	}
+
+	return false
+}
+
+var (
+	// `3.16` as it is written in Russian and European sources
+	dotSeparator = regexp.MustCompile(`(\d)\.(\d)`)
+	// `Jn 3,16`. Only right after the book name, `John 3, 5` are chapters
+	commaSeparator = regexp.MustCompile(`(\pL\.?\s*)(\d+),(\d+)`)
+	// `16a`, `16b`, parts of verses are read as the whole verse
+	versePart = regexp.MustCompile(`(\d)[abc]\b`)
+	// `16f` is the verse and the next one, `16ff` goes to the end of
+	// the chapter
+	followingVerses = regexp.MustCompile(`(\d+)(ff|f)\b`)
+	// en and em dashes are ranges too
+	dashes = regexp.MustCompile(`[–—]`)
+)
+
+// will rewrite other ways of writing references into the form the
+// parser reads: `:` between chapter and verse and `-` for ranges
+func normalizeNotation(s string) string {
+	return rewriteNotation(newRewritten(s)).text
+}
+
+func rewriteNotation(r rewritten) rewritten {
+	r = r.replace(dashes, func(m []string) string { return "-" })
+	r = r.replace(dotSeparator, func(m []string) string { return m[1] + ":" + m[2] })
+	r = r.replace(commaSeparator, func(m []string) string { return m[1] + m[2] + ":" + m[3] })
+	r = r.replace(versePart, func(m []string) string { return m[1] })
+
+	return r.replace(followingVerses, func(m []string) string {
+		if m[2] == "ff" {
+			return m[1] + "-" + RANGE_END
+		}
+
+		verse, _ := strconv.Atoi(m[1])
+		return fmt.Sprintf("%s-%d", m[1], verse+1)
+	})
+}
+
+// text rewritten for the parser that remembers where every byte of it
+// came from, so errors can point at what the user wrote
+type rewritten struct {
+	text string
+	// offset in the original text of every byte of text and of its end
+	origin []int
+}
+
+func newRewritten(s string) rewritten {
+	var origin = make([]int, len(s)+1)
+	for i := range origin {
+		origin[i] = i
+	}
+
+	return rewritten{text: s, origin: origin}
+}
+
+// offset in the original text of the byte at offset of text
+func (r rewritten) originOf(offset int) int {
+	return r.origin[min(max(offset, 0), len(r.text))]
+}
+
+// replaces every match of re with what f returns for its submatches.
+// Beginnings and ends the match and its replacement share keep their
+// places, bytes in between come from the first changed byte of the match
+func (r rewritten) replace(re *regexp.Regexp, f func(m []string) string) rewritten {
+	var result rewritten
+	var text strings.Builder
+	var last int
+
+	keep := func(from, to int) {
+		text.WriteString(r.text[from:to])
+		result.origin = append(result.origin, r.origin[from:to]...)
+	}
+
+	for _, loc := range re.FindAllStringSubmatchIndex(r.text, -1) {
+		var m = make([]string, len(loc)/2)
+		for i := range m {
+			if loc[2*i] >= 0 {
+				m[i] = r.text[loc[2*i]:loc[2*i+1]]
+			}
+		}
+
+		keep(last, loc[0])
+		last = loc[1]
+
+		match, replacement := m[0], f(m)
+
+		var prefix int
+		for prefix < min(len(match), len(replacement)) && match[prefix] == replacement[prefix] {
+			prefix++
+		}
+
+		var suffix int
+		for suffix < min(len(match), len(replacement))-prefix &&
+			match[len(match)-1-suffix] == replacement[len(replacement)-1-suffix] {
+			suffix++
+		}
+
+		text.WriteString(replacement)
+
+		for i := range len(replacement) {
+			switch {
+			case i < prefix:
+				result.origin = append(result.origin, r.origin[loc[0]+i])
+			case i >= len(replacement)-suffix:
+				result.origin = append(result.origin, r.origin[loc[1]-(len(replacement)-i)])
+			default:
+				result.origin = append(result.origin, r.origin[loc[0]+prefix])
+			}
+		}
+	}
+
+	keep(last, len(r.text))
+	result.text = text.String()
+	result.origin = append(result.origin, r.origin[len(r.text)])
+
+	return result
 }
 
 // will check if the beginning of the string contains
@@ -192,11 +535,8 @@ func isName(s string) bool {
 	_, s = readNumber(s)
 	name, s := readString(s)
 
-	if name == "" {
-		return false
-	}
-
-	return true
+	// `.` alone is not a name
+	return strings.ContainsFunc(name, unicode.IsLetter)
 }
 
 func nameOnly(s string) bool {
@@ -206,9 +546,25 @@ func nameOnly(s string) bool {
 }
 
 func parseName(s string) (string, string) {
+	if !isName(s) {
+		return "", s
+	}
+
 	num, s := readNumber(s)
 	name, s := readString(s)
 
+	// names can have several words, e.g. `Song of Solomon`
+	for name != "" && strings.HasPrefix(s, " ") {
+		// `John A:B` has a broken chapter, not a longer name
+		word, rest := readString(s)
+		if word == "" || strings.HasPrefix(rest, ":") {
+			break
+		}
+
+		name = name + " " + word
+		s = rest
+	}
+
 	if num > 0 {
 		name = fmt.Sprintf("%d %s", num, name)
 	}
@@ -231,9 +587,22 @@ func parseCollectionRequest(s string) (CollectionRequest, error) {
 	parts := strings.Split(s, ",")
 
 	var refs = make([]referance, len(parts))
+	var offset int
 
 	for i, entry := range parts {
-		refs[i] = parseGenericRequest(entry)
+		var err error
+
+		refs[i], err = readReference(entry)
+		if err == nil && strings.TrimSpace(entry) == "" {
+			err = errorAt(entry, 0, "expected reference")
+		}
+
+		if err != nil {
+			return CollectionRequest{}, shiftError(err, offset)
+		}
+
+		offset += len(entry) + 1
+
 		if i == 0 {
 			continue
 		}
@@ -262,43 +631,59 @@ func parseMixedRequest(s string) (MixedRequest, error) {
 	//each chunk can be ether collection or range
 	var r MixedRequest
 
+	var offset int
+
 	parts := strings.Split(s, ",")
 	for i, p := range parts {
+		at := offset
+		offset += len(p) + 1
+
 		switch readRequestType(p) {
 		case RANGE:
 			result, err := parseRangeRequest(p)
 			if err != nil {
-				return MixedRequest{}, err
+				return MixedRequest{}, shiftError(err, at)
 			}
 
-			if result.Start.book == "" {
-				result.Start.book = getBookName(r.Entries[i-1])
+			// `John 3:16, 18-20` are verses of the chapter before, but
+			// `John 3, 5-6` are chapters
+			left, right, _ := strings.Cut(p, "-")
+			if i > 0 && !isName(left) && !strings.Contains(left, ":") &&
+				!isChapterRequest(r.Entries[i-1]) {
+				prev := r.Entries[i-1]
+
+				result.Start.book = getBookName(prev)
+				result.Start.chapter = getChapter(prev)
+				result.Start.verse = parseGenericRequest(left).chapter
+
+				if !strings.Contains(right, ":") {
+					result.End = result.Start
+					result.End.verse = parseGenericRequest(right).chapter
+				}
 			}
 
-			if result.End.book == "" {
-				result.End.book = getBookName(r.Entries[i-1])
+			if result.Start.book == "" && i > 0 {
+				result.Start.book = getBookName(r.Entries[i-1])
 			}
 
-			if result.Start.verse == 0 && !isChapterRequest(r.Entries[i-1]) {
-				result.Start.verse = result.Start.chapter
-				result.Start.chapter = getChapter(r.Entries[i-1])
-			}
-			if result.End.verse == 0 && !isChapterRequest(r.Entries[i-1]) {
-				result.End.verse = result.End.chapter
-				result.End.chapter = getChapter(r.Entries[i-1])
+			if result.End.book == "" && i > 0 {
+				result.End.book = getBookName(r.Entries[i-1])
 			}
 
 			r.Entries = append(r.Entries, result)
-		case COLLECTION:
+		case COLLECTION, CONCRETE:
 			result, err := parseCollectionRequest(p)
 			if err != nil {
-				return MixedRequest{}, err
+				return MixedRequest{}, shiftError(err, at)
 			}
 
-			if result.Entries[0].book == "" {
-				result.Entries[0].book = getBookName(r.Entries[i-1])
+			if i == 0 || result.Entries[0].book != "" {
+				r.Entries = append(r.Entries, result)
+				continue
 			}
 
+			result.Entries[0].book = getBookName(r.Entries[i-1])
+
 			if result.Entries[0].verse == 0 && !isChapterRequest(r.Entries[i-1]) {
 				result.Entries[0].verse = result.Entries[0].chapter
 				result.Entries[0].chapter = getChapter(r.Entries[i-1])
@@ -307,9 +692,7 @@ func parseMixedRequest(s string) (MixedRequest, error) {
 
 			r.Entries = append(r.Entries, result)
 		default:
-			msg := "failed to parse request"
-			err := fmt.Sprintf("%s `%s`", msg, s)
-			return MixedRequest{}, errors.New(err)
+			return MixedRequest{}, shiftError(errorAt(p, 0, "expected reference"), at)
 		}
 
 	}
@@ -333,7 +716,8 @@ func isChapterRequest(r Request) bool {
 	case CollectionRequest:
 		return r.Entries[0].verse == 0
 	case RangeRequest:
-		return r.End.verse == 0
+		// `3:16-end` ends at a chapter but starts at a verse
+		return r.Start.verse == 0 && r.End.verse == 0
 	default:
 		return false
 	}
@@ -363,7 +747,8 @@ func readRequestType(s string) RequestType {
 		return MIXED
 	}
 
-	if commas > 0 {
+	// single reference is a collection of one
+	if commas > 0 || dashes == 0 {
 		return COLLECTION
 	}
 
@@ -377,7 +762,7 @@ func readString(s string) (string, string) {
 
 	for i, r := range s {
 
-		if !unicode.IsLetter(r) && r != rune('.') {
+		if !unicode.IsLetter(r) && r != rune('.') && !isApostrophe(r) {
 			str = s[:i]
 			s = s[i:]
 
@@ -390,6 +775,11 @@ func readString(s string) (string, string) {
 
 }
 
+// Ukrainian names like `Об'явлення` have apostrophes in them
+func isApostrophe(r rune) bool {
+	return r == '\'' || r == '’'
+}
+
 func skipWhitespace(s string) string {
 
 	for i, char := range s {
@@ -411,11 +801,12 @@ func readNumber(s string) (int, string) {
 	var number int
 
 	s = skipWhitespace(s)
+	rest := ""
 
 	for i, r := range s {
 
 		if !unicode.IsNumber(r) {
-			s = s[i:]
+			rest = s[i:]
 			break
 		}
 
@@ -423,6 +814,10 @@ func readNumber(s string) (int, string) {
 
 	}
 
+	if len(numRunes) > 0 {
+		s = rest
+	}
+
 	if len(numRunes) < 1 {
 		return number, s
 	}
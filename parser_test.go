@@ -621,3 +621,130 @@ func TestParseMixedRequest(t *testing.T) {
 		}
 	}
 }
+
+func TestNormalizeOrdinals(t *testing.T) {
+	tests := []string{
+		"I John 3:16",
+		"II Cor 5:17",
+		"III John 1",
+		"First Thessalonians 4:16",
+		"2nd Kings 2:11",
+		"1st Pet. 1:3",
+		"1-е Коринфянам 13:4",
+		"Второе Петра 1:1",
+		"Перше Івана 1:1",
+		"John 3:16, I John 1:9",
+		"I love you",
+		"Am I my brother's keeper",
+		"John 3:16, II Corinthians 5:17",
+		"Isaiah 53",
+	}
+
+	expectedResults := []string{
+		"1 John 3:16",
+		"2 Cor 5:17",
+		"3 John 1",
+		"1 Thessalonians 4:16",
+		"2 Kings 2:11",
+		"1 Pet. 1:3",
+		"1 Коринфянам 13:4",
+		"2 Петра 1:1",
+		"1 Івана 1:1",
+		"John 3:16, 1 John 1:9",
+		// ordinals before words that are not books stay as they are
+		"I love you",
+		"Am I my brother's keeper",
+		"John 3:16, 2 Corinthians 5:17",
+		"Isaiah 53",
+	}
+
+	for i, test := range tests {
+		if result := normalizeOrdinals(test); result != expectedResults[i] {
+			t.Fatalf("TEST[%d] failed: expected %q got %q", i, expectedResults[i], result)
+		}
+	}
+}
+
+func TestNormalizeNotation(t *testing.T) {
+	tests := []string{
+		"Ин 3.16",
+		"Jn 3,16-18",
+		"Matt. 5.3",
+		"John 3, 5",
+		"John 3:16,18",
+		"John 3:16a",
+		"John 3:16b-18",
+		"John 3:16f",
+		"John 3:16ff",
+		"John 3:14–16",
+		"1 Cor 2:1—3:4",
+	}
+
+	expectedResults := []string{
+		"Ин 3:16",
+		"Jn 3:16-18",
+		"Matt. 5:3",
+		"John 3, 5",
+		"John 3:16,18",
+		"John 3:16",
+		"John 3:16-18",
+		"John 3:16-17",
+		"John 3:16-end",
+		"John 3:14-16",
+		"1 Cor 2:1-3:4",
+	}
+
+	for i, test := range tests {
+		if result := normalizeNotation(test); result != expectedResults[i] {
+			t.Fatalf("TEST[%d] failed: expected %q got %q", i, expectedResults[i], result)
+		}
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []string{
+		"John A:B-C",
+		"John 3:x",
+		"John 3:16-",
+		"John 3:16,,18",
+		"John 3:20-10",
+		"John 3:16 foo",
+		"John 3-C",
+		"Иоанна 3:ж",
+		// columns are counted in the query as it was written
+		"First John 3:x",
+		"Jn 3:16f-x",
+		"Jn 3.16ff-x",
+	}
+
+	expectedResults := []struct {
+		col   int
+		token string
+	}{
+		{6, "A"},
+		{8, "x"},
+		{11, ""},
+		{11, ""},
+		{11, "10"},
+		{11, "foo"},
+		{9, ""},
+		{10, "ж"},
+		{14, "x"},
+		{9, "-"},
+		{10, "-"},
+	}
+
+	for i, test := range tests {
+		_, err := Parse(test)
+
+		var pe *ParseError
+		if !errors.As(err, &pe) {
+			t.Fatalf("TEST[%d] failed: expected ParseError got %v", i, err)
+		}
+
+		expect := expectedResults[i]
+		if pe.Col() != expect.col || pe.Token != expect.token || pe.Book == "" || pe.Query != test {
+			t.Fatalf("TEST[%d] failed: expected col %d token %q got %s (book %q)", i, expect.col, expect.token, pe, pe.Book)
+		}
+	}
+}
@@ -0,0 +1,300 @@
+package bible
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+)
+
+// PassageSet is a list of passages kept sorted, without overlaps and
+// with adjacent passages merged, so every verse is in it only once.
+// Chapter ends are not known without a module, so 3:36 and 4:1 stay
+// apart while 3:16 and 3:17 are merged.
+type PassageSet struct {
+	spans []span
+}
+
+// span is a passage with both ends pointing at actual verses. Starts
+// of chapters and books are verse 1, ends are math.MaxInt
+type span struct {
+	lo Reference
+	hi Reference
+}
+
+func newSpan(p Passage) span {
+	lo := p.Start
+
+	if lo.Chapter == 0 {
+		lo.Chapter = 1
+	}
+
+	if lo.Verse == 0 {
+		lo.Verse = 1
+	}
+
+	return span{lo: lo, hi: p.End.end()}
+}
+
+// whole chapters and books go back to verse 0 and chapter 0, so the
+// same text always looks the same
+func (s span) passage() Passage {
+	p := Passage{Start: s.lo, End: s.hi}
+
+	if p.End.Chapter == math.MaxInt {
+		p.End.Chapter = 0
+		p.End.Verse = 0
+
+		if p.Start.Chapter == 1 && p.Start.Verse == 1 {
+			p.Start.Chapter = 0
+			p.Start.Verse = 0
+		}
+	}
+
+	if p.End.Verse == math.MaxInt {
+		p.End.Verse = 0
+
+		if p.Start.Verse == 1 {
+			p.Start.Verse = 0
+		}
+	}
+
+	if p.Start.BookNumber == p.End.BookNumber && p.Start.Chapter == 0 {
+		p.End = p.Start
+	}
+
+	if p.Start.BookNumber == p.End.BookNumber && p.Start.Chapter == p.End.Chapter && p.Start.Verse == 0 && p.End.Verse == 0 {
+		p.End = p.Start
+	}
+
+	return p
+}
+
+func (s span) empty() bool {
+	return s.lo.Compare(s.hi) > 0
+}
+
+// first verse after the end of a span
+func after(r Reference) Reference {
+	switch {
+	case r.Chapter == math.MaxInt:
+		return Reference{BookNumber: r.BookNumber + 1, Chapter: 1, Verse: 1}
+	case r.Verse == math.MaxInt:
+		return Reference{BookNumber: r.BookNumber, Chapter: r.Chapter + 1, Verse: 1}
+	}
+
+	return Reference{BookNumber: r.BookNumber, Chapter: r.Chapter, Verse: r.Verse + 1}
+}
+
+// last verse before the start of a span
+func before(r Reference) Reference {
+	switch {
+	case r.Verse > 1:
+		return Reference{BookNumber: r.BookNumber, Chapter: r.Chapter, Verse: r.Verse - 1}
+	case r.Chapter > 1:
+		return Reference{BookNumber: r.BookNumber, Chapter: r.Chapter - 1, Verse: math.MaxInt}
+	}
+
+	return Reference{BookNumber: r.BookNumber - 1, Chapter: math.MaxInt, Verse: math.MaxInt}
+}
+
+func NewPassageSet(passages ...Passage) PassageSet {
+	var spans = make([]span, len(passages))
+
+	for i, p := range passages {
+		spans[i] = newSpan(p)
+	}
+
+	return compact(spans)
+}
+
+// will sort spans and merge the ones that overlap or touch
+func compact(spans []span) PassageSet {
+	spans = slices.DeleteFunc(slices.Clone(spans), span.empty)
+
+	slices.SortFunc(spans, func(a, b span) int {
+		return a.lo.Compare(b.lo)
+	})
+
+	var result []span
+
+	for _, s := range spans {
+		if len(result) > 0 {
+			last := &result[len(result)-1]
+
+			if s.lo.Compare(after(last.hi)) <= 0 {
+				if s.hi.Compare(last.hi) > 0 {
+					last.hi = s.hi
+				}
+				continue
+			}
+		}
+
+		result = append(result, s)
+	}
+
+	return PassageSet{spans: result}
+}
+
+// GetPassages returns verses of the set in order. Every verse is
+// fetched once, no matter how many passages asked for it
+func (app *Bible) GetPassages(set PassageSet) ([]Verse, error) {
+	var result []Verse
+
+	for _, sp := range set.spans {
+		verses, err := app.Between(
+			Verse{BookNumber: sp.lo.BookNumber, Chapter: sp.lo.Chapter, Verse: sp.lo.Verse},
+			Verse{BookNumber: sp.hi.BookNumber, Chapter: sp.hi.Chapter, Verse: sp.hi.Verse},
+		)
+		if err != nil {
+			return []Verse{}, err
+		}
+
+		result = append(result, verses...)
+	}
+
+	return result, nil
+}
+
+// Passages returns passages of the set in order
+func (s PassageSet) Passages() []Passage {
+	var result = make([]Passage, len(s.spans))
+
+	for i, sp := range s.spans {
+		result[i] = sp.passage()
+	}
+
+	return result
+}
+
+func (s PassageSet) Empty() bool {
+	return len(s.spans) == 0
+}
+
+// Contains reports whether every verse of r is in the set
+func (s PassageSet) Contains(r Reference) bool {
+	rs := newSpan(NewPassage(r))
+
+	for _, sp := range s.spans {
+		if sp.lo.Compare(rs.lo) <= 0 && rs.hi.Compare(sp.hi) <= 0 {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Union returns verses that are in either of the sets
+func (s PassageSet) Union(o PassageSet) PassageSet {
+	return compact(append(slices.Clone(s.spans), o.spans...))
+}
+
+// Intersect returns verses that are in both sets
+func (s PassageSet) Intersect(o PassageSet) PassageSet {
+	var result []span
+
+	for _, a := range s.spans {
+		for _, b := range o.spans {
+			sp := span{lo: maxRef(a.lo, b.lo), hi: minRef(a.hi, b.hi)}
+
+			if !sp.empty() {
+				result = append(result, sp)
+			}
+		}
+	}
+
+	return compact(result)
+}
+
+// Difference returns verses of s that are not in o
+func (s PassageSet) Difference(o PassageSet) PassageSet {
+	var result []span
+
+	for _, a := range s.spans {
+		var pieces = []span{a}
+
+		for _, b := range o.spans {
+			var next []span
+
+			for _, p := range pieces {
+				if p.hi.Compare(b.lo) < 0 || b.hi.Compare(p.lo) < 0 {
+					next = append(next, p)
+					continue
+				}
+
+				left := span{lo: p.lo, hi: minRef(p.hi, before(b.lo))}
+				right := span{lo: maxRef(p.lo, after(b.hi)), hi: p.hi}
+
+				for _, piece := range []span{left, right} {
+					if !piece.empty() {
+						next = append(next, piece)
+					}
+				}
+			}
+
+			pieces = next
+		}
+
+		result = append(result, pieces...)
+	}
+
+	return compact(result)
+}
+
+// formats the set in the compact form, e.g. `John 3:1,3,5-7; 4:2; Acts 1`
+func (s PassageSet) String() string {
+	return s.format(bookName)
+}
+
+// book name is only written when the book changes and the chapter only
+// when the chapter changes
+func (s PassageSet) format(name func(int) string) string {
+	var out = new(strings.Builder)
+	var prev Passage
+
+	for i, p := range s.Passages() {
+		text := p.format(name)
+		short := strings.TrimSpace(strings.TrimPrefix(text, name(p.Start.BookNumber)))
+
+		sameBook := i > 0 &&
+			p.Start.BookNumber == prev.End.BookNumber &&
+			p.End.BookNumber == p.Start.BookNumber &&
+			prev.End.Chapter != 0 && p.Start.Chapter != 0
+
+		sameChapter := sameBook &&
+			prev.End.Verse != 0 && p.Start.Verse != 0 &&
+			p.Start.Chapter == prev.End.Chapter
+
+		switch {
+		case i == 0:
+			out.WriteString(text)
+		case sameChapter:
+			out.WriteString(",")
+			out.WriteString(strings.TrimPrefix(short, fmt.Sprintf("%d:", p.Start.Chapter)))
+		case sameBook:
+			out.WriteString("; ")
+			out.WriteString(short)
+		default:
+			out.WriteString("; ")
+			out.WriteString(text)
+		}
+
+		prev = p
+	}
+
+	return out.String()
+}
+
+func minRef(a, b Reference) Reference {
+	if a.Compare(b) < 0 {
+		return a
+	}
+	return b
+}
+
+func maxRef(a, b Reference) Reference {
+	if a.Compare(b) > 0 {
+		return a
+	}
+	return b
+}
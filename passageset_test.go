@@ -0,0 +1,106 @@
+package bible
+
+import (
+	"testing"
+)
+
+func johnVerses(chapter int, from, to int) Passage {
+	return Passage{
+		Start: Reference{500, chapter, from},
+		End:   Reference{500, chapter, to},
+	}
+}
+
+func TestPassageSetCompact(t *testing.T) {
+	tests := [][]Passage{
+		{johnVerses(3, 14, 18), johnVerses(3, 16, 16), johnVerses(3, 17, 20)},
+		{johnVerses(3, 1, 1), johnVerses(3, 3, 3), johnVerses(3, 5, 7)},
+		{johnVerses(3, 5, 7), johnVerses(3, 1, 1), johnVerses(3, 8, 8), johnVerses(3, 3, 3)},
+		{NewPassage(Reference{500, 3, 0}), johnVerses(3, 16, 16)},
+		{NewPassage(Reference{500, 3, 0}), NewPassage(Reference{500, 4, 0})},
+		{johnVerses(3, 16, 16), johnVerses(4, 1, 2), NewPassage(Reference{510, 1, 1})},
+		{NewPassage(Reference{500, 3, 0}), johnVerses(4, 1, 2)},
+		{NewPassage(Reference{500, 0, 0}), johnVerses(3, 16, 16)},
+		{{Start: Reference{550, 0, 0}, End: Reference{580, 0, 0}}},
+		{{Start: Reference{500, 1, 0}, End: Reference{510, 1, 0}}},
+		{{Start: Reference{500, 21, 25}, End: Reference{510, 1, 1}}},
+		{},
+	}
+
+	expectedResults := []string{
+		"John 3:14-20",
+		"John 3:1,3,5-7",
+		"John 3:1,3,5-8",
+		"John 3",
+		"John 3-4",
+		"John 3:16; 4:1-2; Acts 1:1",
+		"John 3:1-4:2",
+		"John",
+		"Galatians-Colossians",
+		"John 1-Acts 1",
+		"John 21:25-Acts 1:1",
+		"",
+	}
+
+	for i, test := range tests {
+		if result := NewPassageSet(test...).String(); result != expectedResults[i] {
+			t.Fatalf("TEST[%d] failed: expected %q got %q", i, expectedResults[i], result)
+		}
+	}
+}
+
+func TestPassageSetAlgebra(t *testing.T) {
+	a := NewPassageSet(johnVerses(3, 1, 10), johnVerses(3, 20, 25))
+	b := NewPassageSet(johnVerses(3, 5, 22))
+	chapter := NewPassageSet(NewPassage(Reference{500, 3, 0}))
+
+	tests := []PassageSet{
+		a.Union(b),
+		a.Intersect(b),
+		a.Difference(b),
+		b.Difference(a),
+		chapter.Difference(NewPassageSet(johnVerses(3, 16, 16))),
+		chapter.Intersect(NewPassageSet(johnVerses(3, 30, 40), johnVerses(4, 1, 1))),
+		a.Difference(a),
+	}
+
+	expectedResults := []string{
+		"John 3:1-25",
+		"John 3:5-10,20-22",
+		"John 3:1-4,23-25",
+		"John 3:11-19",
+		"John 3:1-15,17-end",
+		"John 3:30-40",
+		"",
+	}
+
+	for i, test := range tests {
+		if result := test.String(); result != expectedResults[i] {
+			t.Fatalf("TEST[%d] failed: expected %q got %q", i, expectedResults[i], result)
+		}
+	}
+
+	if !chapter.Contains(Reference{500, 3, 16}) || a.Contains(Reference{500, 3, 0}) {
+		t.Fatalf("wrong Contains result")
+	}
+}
+
+func TestGetPassages(t *testing.T) {
+	app := testModule(t)
+
+	set := NewPassageSet(
+		johnVerses(20, 2, 3),
+		johnVerses(20, 3, 3),
+		NewPassage(Reference{500, 21, 0}),
+		NewPassage(Reference{510, 1, 1}),
+	)
+
+	result, err := app.GetPassages(set)
+	if err != nil {
+		t.Fatalf("failed to get passages: %s", err)
+	}
+
+	if len(result) != 5 {
+		t.Fatalf("expected 5 verses got %d: %+v", len(result), result)
+	}
+}
@@ -1,6 +1,26 @@
 -- name: GetBookNames :many
 SELECT * FROM books ORDER BY book_number;
 
+-- name: CountVersesBetween :one
+SELECT COUNT(*) FROM verses
+WHERE (book_number, chapter, verse) >= (sqlc.arg(from_book), sqlc.arg(from_chapter), sqlc.arg(from_verse))
+AND (book_number, chapter, verse) <= (sqlc.arg(to_book), sqlc.arg(to_chapter), sqlc.arg(to_verse));
+
+-- name: GetBookStats :many
+SELECT book_number, COUNT(DISTINCT chapter) AS chapters, COUNT(*) AS verses
+FROM verses
+GROUP BY book_number
+ORDER BY book_number;
+
+-- name: GetBookStories :many
+SELECT * FROM stories
+WHERE (book_number = ?)
+ORDER BY book_number, chapter, verse, order_if_several;
+
+-- name: GetChapterCount :one
+SELECT COUNT(DISTINCT chapter) FROM verses
+WHERE (book_number = ?);
+
 -- name: GetChapters :many
 SELECT * FROM verses
 WHERE (book_number = ?)
@@ -32,3 +52,37 @@ ORDER BY
  book_number,
  chapter,
  verse;
+
+-- name: GetInfo :many
+SELECT * FROM info ORDER BY name;
+
+-- name: GetStories :many
+SELECT * FROM stories
+WHERE (book_number = ?)
+AND (chapter = ?)
+ORDER BY book_number, chapter, verse, order_if_several;
+
+-- name: GetVerseAfter :one
+SELECT * FROM verses
+WHERE (book_number, chapter, verse) > (?, ?, ?)
+ORDER BY book_number, chapter, verse
+LIMIT 1 OFFSET ?;
+
+-- name: GetVerseBefore :one
+SELECT * FROM verses
+WHERE (book_number, chapter, verse) < (?, ?, ?)
+ORDER BY book_number DESC, chapter DESC, verse DESC
+LIMIT 1 OFFSET ?;
+
+-- name: GetVersesBetween :many
+SELECT * FROM verses
+WHERE (book_number, chapter, verse) >= (sqlc.arg(from_book), sqlc.arg(from_chapter), sqlc.arg(from_verse))
+AND (book_number, chapter, verse) <= (sqlc.arg(to_book), sqlc.arg(to_chapter), sqlc.arg(to_verse))
+ORDER BY book_number, chapter, verse;
+
+-- name: GetVerseBetweenAt :one
+SELECT * FROM verses
+WHERE (book_number, chapter, verse) >= (sqlc.arg(from_book), sqlc.arg(from_chapter), sqlc.arg(from_verse))
+AND (book_number, chapter, verse) <= (sqlc.arg(to_book), sqlc.arg(to_chapter), sqlc.arg(to_verse))
+ORDER BY book_number, chapter, verse
+LIMIT 1 OFFSET sqlc.arg(offset);
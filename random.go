@@ -0,0 +1,265 @@
+package bible
+
+import (
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"math/rand/v2"
+	"slices"
+	"time"
+
+	"github.com/ButbkaDrug/bible/internal/repository"
+)
+
+// Pick decides what a random choice returns
+type Pick int
+
+const (
+	// every verse is as likely as any other
+	PICK_VERSE Pick = iota
+	// every passage is as likely as any other, no matter how long it is.
+	// Passages are sections under headings or chapters when the book has
+	// no headings
+	PICK_PASSAGE
+)
+
+// RandomScope is what random verses are picked from. Passages are a
+// curated list and win over Books. Both empty is every book of the
+// canon
+type RandomScope struct {
+	Books    []int
+	Passages []Passage
+}
+
+// Random returns a random verse of the canon
+func (app *Bible) Random() ([]Verse, error) {
+	r := rand.New(rand.NewPCG(rand.Uint64(), rand.Uint64()))
+	return app.PickRandom(r, RandomScope{}, PICK_VERSE)
+}
+
+// VerseOfTheDay picks the same verses for everyone on the same date as
+// long as the module and the scope are the same. Different seeds give
+// different verses
+func (app *Bible) VerseOfTheDay(date time.Time, seed string, scope RandomScope, pick Pick) ([]Verse, error) {
+	h := fnv.New64a()
+	fmt.Fprintf(h, "%s\x00%s", date.Format(time.DateOnly), seed)
+	sum := h.Sum64()
+
+	r := rand.New(rand.NewPCG(sum, ^sum))
+
+	return app.PickRandom(r, scope, pick)
+}
+
+// PickRandom picks a verse or a passage of the scope with r
+func (app *Bible) PickRandom(r *rand.Rand, scope RandomScope, pick Pick) ([]Verse, error) {
+	if pick == PICK_PASSAGE && len(scope.Passages) > 0 {
+		return app.randomPassage(r, scope.Passages)
+	}
+
+	spans, err := app.randomSpans(scope)
+	if err != nil {
+		return []Verse{}, err
+	}
+
+	if pick == PICK_PASSAGE {
+		return app.randomSection(r, spans)
+	}
+
+	return app.randomVerse(r, spans)
+}
+
+var errNothingToPick = errors.New("nothing to pick from")
+
+func (app *Bible) randomSpans(scope RandomScope) ([]span, error) {
+	if len(scope.Passages) > 0 {
+		return NewPassageSet(scope.Passages...).spans, nil
+	}
+
+	books := scope.Books
+	if len(books) < 1 {
+		catalog, err := app.Books()
+		if err != nil {
+			return nil, err
+		}
+
+		for _, b := range catalog {
+			books = append(books, b.Number)
+		}
+	}
+
+	var spans = make([]span, len(books))
+	for i, n := range books {
+		spans[i] = newSpan(NewPassage(Reference{BookNumber: n}))
+	}
+
+	return spans, nil
+}
+
+// every verse of the spans has the same chance
+func (app *Bible) randomVerse(r *rand.Rand, spans []span) ([]Verse, error) {
+	var counts = make([]int64, len(spans))
+	var total int64
+
+	for i, sp := range spans {
+		count, err := app.countVerses(sp)
+		if err != nil {
+			return []Verse{}, err
+		}
+
+		counts[i] = count
+		total += count
+	}
+
+	if total < 1 {
+		return []Verse{}, errNothingToPick
+	}
+
+	n := r.Int64N(total)
+
+	for i, sp := range spans {
+		if n >= counts[i] {
+			n -= counts[i]
+			continue
+		}
+
+		v, err := app.db.GetVerseBetweenAt(app.ctx, repository.GetVerseBetweenAtParams{
+			FromBook:    float64(sp.lo.BookNumber),
+			FromChapter: float64(sp.lo.Chapter),
+			FromVerse:   float64(sp.lo.Verse),
+			ToBook:      float64(sp.hi.BookNumber),
+			ToChapter:   float64(sp.hi.Chapter),
+			ToVerse:     float64(sp.hi.Verse),
+			Offset:      n,
+		})
+		if err != nil {
+			return []Verse{}, err
+		}
+
+		return []Verse{app.wrapVerse(v)}, nil
+	}
+
+	return []Verse{}, errNothingToPick
+}
+
+// every passage of the list the module has verses of has the same chance
+func (app *Bible) randomPassage(r *rand.Rand, passages []Passage) ([]Verse, error) {
+	var found []Passage
+
+	for _, p := range passages {
+		count, err := app.countVerses(newSpan(p))
+		if err != nil {
+			return []Verse{}, err
+		}
+
+		if count > 0 {
+			found = append(found, p)
+		}
+	}
+
+	if len(found) < 1 {
+		return []Verse{}, errNothingToPick
+	}
+
+	return app.GetPassages(NewPassageSet(found[r.IntN(len(found))]))
+}
+
+func (app *Bible) countVerses(sp span) (int64, error) {
+	return app.db.CountVersesBetween(app.ctx, repository.CountVersesBetweenParams{
+		FromBook:    float64(sp.lo.BookNumber),
+		FromChapter: float64(sp.lo.Chapter),
+		FromVerse:   float64(sp.lo.Verse),
+		ToBook:      float64(sp.hi.BookNumber),
+		ToChapter:   float64(sp.hi.Chapter),
+		ToVerse:     float64(sp.hi.Verse),
+	})
+}
+
+// section of a book, from its first verse up to the next section
+type section struct {
+	start Reference
+	// start of the next section or the end of the book
+	next Reference
+}
+
+// every section of the books has the same chance
+func (app *Bible) randomSection(r *rand.Rand, spans []span) ([]Verse, error) {
+	var sections []section
+
+	for _, sp := range spans {
+		s, err := app.bookSections(sp)
+		if err != nil {
+			return []Verse{}, err
+		}
+
+		sections = append(sections, s...)
+	}
+
+	if len(sections) < 1 {
+		return []Verse{}, errNothingToPick
+	}
+
+	s := sections[r.IntN(len(sections))]
+
+	var result []Verse
+
+	for v, err := range app.between(s.start, s.next) {
+		if err != nil {
+			return []Verse{}, err
+		}
+
+		if v.Reference().Compare(s.next) >= 0 {
+			break
+		}
+
+		result = append(result, v)
+	}
+
+	return result, nil
+}
+
+// sections start at headings. Books without headings are split into
+// chapters
+func (app *Bible) bookSections(sp span) ([]section, error) {
+	book := sp.lo.BookNumber
+
+	chapters, err := app.db.GetChapters(app.ctx, float64(book))
+	if err != nil || len(chapters) < 1 {
+		return nil, err
+	}
+
+	stories, err := app.db.GetBookStories(app.ctx, float64(book))
+	if err != nil {
+		return nil, err
+	}
+
+	var starts []Reference
+	for _, s := range stories {
+		starts = append(starts, Reference{BookNumber: book, Chapter: int(s.Chapter), Verse: int(s.Verse)})
+	}
+
+	first := Reference{BookNumber: book, Chapter: int(chapters[0].Chapter), Verse: 1}
+
+	if len(starts) < 1 {
+		for _, c := range chapters {
+			starts = append(starts, Reference{BookNumber: book, Chapter: int(c.Chapter), Verse: 1})
+		}
+	} else if starts[0].Compare(first) > 0 {
+		starts = append([]Reference{first}, starts...)
+	}
+
+	// several headings over one verse start one section
+	starts = slices.CompactFunc(starts, func(a, b Reference) bool {
+		return a.Compare(b) == 0
+	})
+
+	var result = make([]section, len(starts))
+	for i, start := range starts {
+		result[i] = section{start: start, next: sp.hi}
+
+		if i+1 < len(starts) {
+			result[i].next = starts[i+1]
+		}
+	}
+
+	return result, nil
+}
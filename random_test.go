@@ -0,0 +1,103 @@
+package bible
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestPickRandom(t *testing.T) {
+	app := testModule(t)
+	r := rand.New(rand.NewPCG(1, 2))
+
+	john, _ := app.Resolve("John 21")
+
+	tests := []struct {
+		scope RandomScope
+		pick  Pick
+	}{
+		{RandomScope{}, PICK_VERSE},
+		{RandomScope{Books: []int{510}}, PICK_VERSE},
+		{RandomScope{Passages: john}, PICK_VERSE},
+		{RandomScope{Books: []int{500}}, PICK_PASSAGE},
+		{RandomScope{Passages: john}, PICK_PASSAGE},
+	}
+
+	// everything the scope has comes up sooner or later, and nothing else
+	expectedResults := []string{
+		"[John 20:1 John 20:2 John 20:3 John 21:1 John 21:2 Acts 1:1 Acts 1:2 Acts 1:3]",
+		"[Acts 1:1 Acts 1:2 Acts 1:3]",
+		"[John 21:1 John 21:2]",
+		"[John 20:1-3 John 21:1-2]",
+		"[John 21:1-2]",
+	}
+
+	for i, test := range tests {
+		var picked []Passage
+
+		for range 200 {
+			verses, err := app.PickRandom(r, test.scope, test.pick)
+			if err != nil {
+				t.Fatalf("TEST[%d] failed: %s", i, err)
+			}
+
+			p := Passage{Start: verses[0].Reference(), End: verses[len(verses)-1].Reference()}
+			if !slices.Contains(picked, p) {
+				picked = append(picked, p)
+			}
+		}
+
+		slices.SortFunc(picked, func(a, b Passage) int {
+			return a.Start.Compare(b.Start)
+		})
+
+		if result := fmt.Sprint(picked); result != expectedResults[i] {
+			t.Fatalf("TEST[%d] failed: expected %s got %s", i, expectedResults[i], result)
+		}
+	}
+
+	if _, err := app.PickRandom(r, RandomScope{Books: []int{10}}, PICK_VERSE); err == nil {
+		t.Fatalf("expected an error for a book the module does not have")
+	}
+}
+
+func TestVerseOfTheDay(t *testing.T) {
+	app := testModule(t)
+	day := time.Date(2026, 10, 19, 0, 0, 0, 0, time.UTC)
+
+	var first = make([]string, 0, 30)
+	var again = make([]string, 0, 30)
+	var seeded = make([]string, 0, 30)
+
+	for i := range 30 {
+		date := day.AddDate(0, 0, i)
+
+		for _, out := range []*[]string{&first, &again} {
+			verses, err := app.VerseOfTheDay(date, "", RandomScope{}, PICK_VERSE)
+			if err != nil {
+				t.Fatal(err)
+			}
+			*out = append(*out, fmt.Sprint(verses[0].Reference()))
+		}
+
+		verses, err := app.VerseOfTheDay(date, "team", RandomScope{}, PICK_VERSE)
+		if err != nil {
+			t.Fatal(err)
+		}
+		seeded = append(seeded, fmt.Sprint(verses[0].Reference()))
+	}
+
+	if !slices.Equal(first, again) {
+		t.Fatalf("expected the same verses for the same dates got %v and %v", first, again)
+	}
+
+	if slices.Equal(first, seeded) {
+		t.Fatalf("expected other verses with a seed got %v", seeded)
+	}
+
+	if len(slices.Compact(slices.Sorted(slices.Values(first)))) < 2 {
+		t.Fatalf("expected verses to change from day to day got %v", first)
+	}
+}
@@ -0,0 +1,263 @@
+package bible
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+)
+
+// Reference points at a verse by the MyBible book number, so it does
+// not depend on the translation. Verse 0 means the whole chapter and
+// chapter 0 the whole book.
+type Reference struct {
+	BookNumber int
+	Chapter    int
+	Verse      int
+}
+
+// formats reference with English book names, e.g. `John 3:16`
+func (r Reference) String() string {
+	return r.format(bookName)
+}
+
+// name gives the name of the book by its number. When it is empty only
+// chapter and verse are left
+func (r Reference) format(name func(int) string) string {
+	var s string
+
+	switch {
+	case r.Chapter == 0:
+	case r.Verse == 0:
+		s = fmt.Sprintf("%d", r.Chapter)
+	default:
+		s = fmt.Sprintf("%d:%d", r.Chapter, r.Verse)
+	}
+
+	return strings.TrimSpace(name(r.BookNumber) + " " + s)
+}
+
+// Compare returns -1, 0 or +1 when r goes before, is the same as or goes
+// after o. Books go in order of their numbers
+func (r Reference) Compare(o Reference) int {
+	if c := cmp.Compare(r.BookNumber, o.BookNumber); c != 0 {
+		return c
+	}
+
+	if c := cmp.Compare(r.Chapter, o.Chapter); c != 0 {
+		return c
+	}
+
+	return cmp.Compare(r.Verse, o.Verse)
+}
+
+// last verse the reference covers. Whole chapters and books end after
+// any verse they have
+func (r Reference) end() Reference {
+	if r.Chapter == 0 {
+		r.Chapter = math.MaxInt
+	}
+
+	if r.Verse == 0 {
+		r.Verse = math.MaxInt
+	}
+
+	return r
+}
+
+// Reference of the verse
+func (v Verse) Reference() Reference {
+	return Reference{BookNumber: v.BookNumber, Chapter: v.Chapter, Verse: v.Verse}
+}
+
+// Passage is a continuous piece of text from Start to End, both
+// included. End with verse 0 goes to the end of the chapter and End
+// with chapter 0 to the end of the book
+type Passage struct {
+	Start Reference
+	End   Reference
+}
+
+// passage of a single reference
+func NewPassage(r Reference) Passage {
+	return Passage{Start: r, End: r}
+}
+
+// formats passage the short way: `John 3:16-18`, `John 3:16-4:2`,
+// `John 21:25-Acts 1:2`
+func (p Passage) String() string {
+	return p.format(bookName)
+}
+
+func (p Passage) format(name func(int) string) string {
+	s, e := p.Start, p.End
+
+	switch {
+	case s == e:
+		return s.format(name)
+	case s.BookNumber != e.BookNumber:
+		return fmt.Sprintf("%s-%s", s.format(name), e.format(name))
+	case s.Chapter != 0 && s.Verse == 0 && e.Chapter == 0:
+		return fmt.Sprintf("%s-%s", s.format(name), verseOrEnd(0))
+	case s.Chapter == 0 || e.Chapter == 0:
+		return s.format(name)
+	case s.Chapter != e.Chapter && s.Verse == 0 && e.Verse == 0:
+		return fmt.Sprintf("%s-%d", s.format(name), e.Chapter)
+	case s.Chapter != e.Chapter:
+		return fmt.Sprintf("%s-%d:%s", s.format(name), e.Chapter, verseOrEnd(e.Verse))
+	case s.Verse == 0:
+		return s.format(name)
+	}
+
+	return fmt.Sprintf("%s-%s", s.format(name), verseOrEnd(e.Verse))
+}
+
+func verseOrEnd(n int) string {
+	if n == 0 {
+		return "end"
+	}
+
+	return fmt.Sprint(n)
+}
+
+// Compare orders passages by start and then by end
+func (p Passage) Compare(o Passage) int {
+	if c := p.Start.Compare(o.Start); c != 0 {
+		return c
+	}
+
+	return p.End.end().Compare(o.End.end())
+}
+
+// Contains reports whether every verse of r is inside the passage
+func (p Passage) Contains(r Reference) bool {
+	return p.Start.Compare(r) <= 0 && r.end().Compare(p.End.end()) <= 0
+}
+
+// Overlaps reports whether passages have at least one verse in common
+func (p Passage) Overlaps(o Passage) bool {
+	return p.Start.Compare(o.End.end()) <= 0 && o.Start.Compare(p.End.end()) <= 0
+}
+
+func bookName(number int) string {
+	for _, b := range defBooks {
+		if int(b.BookNumber) == number {
+			return b.LongName
+		}
+	}
+
+	return fmt.Sprintf("Book %d", number)
+}
+
+// Resolve parses the query and returns passages it refers to. Book
+// names are looked up the same way Execute does
+func (app *Bible) Resolve(query string) ([]Passage, error) {
+	request, err := Parse(query)
+	if err != nil {
+		return nil, err
+	}
+
+	return app.Passages(request)
+}
+
+// Passages converts parsed request into passages. Empty request has
+// none
+func (app *Bible) Passages(request Request) ([]Passage, error) {
+	switch r := request.(type) {
+	case EmptyRequest:
+		return nil, nil
+	case ConcreteRequest:
+		ref, err := app.reference(r.ref)
+		return []Passage{NewPassage(ref)}, err
+	case RangeRequest:
+		start, err := app.reference(r.Start)
+		if err != nil {
+			return nil, err
+		}
+
+		end, err := app.reference(r.End)
+		if err != nil {
+			return nil, err
+		}
+
+		// order of books is only known here, `Col-Gal` is parsed fine
+		passage := Passage{Start: start, End: end}
+		if start.Compare(end.end()) > 0 {
+			return nil, fmt.Errorf("range `%s` ends before it starts", passage)
+		}
+
+		return []Passage{passage}, nil
+	case CollectionRequest:
+		var result = make([]Passage, len(r.Entries))
+
+		for i, entry := range r.Entries {
+			ref, err := app.reference(entry)
+			if err != nil {
+				return nil, err
+			}
+
+			result[i] = NewPassage(ref)
+		}
+
+		return result, nil
+	case MixedRequest:
+		var result []Passage
+
+		for _, entry := range r.Entries {
+			passages, err := app.Passages(entry)
+			if err != nil {
+				return nil, err
+			}
+
+			result = append(result, passages...)
+		}
+
+		return result, nil
+	}
+
+	return nil, fmt.Errorf("unknown request %T", request)
+}
+
+// errNotReference is returned for names without numbers that are not
+// books, such queries are words to search for
+var errNotReference = errors.New("not a reference")
+
+// book of a name without numbers. Names close to a book are guessed or
+// give a BookError, names nothing is close to are words to search for
+func (app *Bible) nameOnlyBook(name string) (int, error) {
+	book, err := app.findBook(name)
+
+	var bookErr *BookError
+	if errors.As(err, &bookErr) && len(bookErr.Suggestions) < 1 {
+		return 0, fmt.Errorf("%w: %w", err, errNotReference)
+	}
+
+	return book, err
+}
+
+func (app *Bible) reference(r referance) (Reference, error) {
+	var book int
+
+	if r.chapter == 0 && r.verse == 0 || r.book == "" {
+		var err error
+
+		book, err = app.nameOnlyBook(r.book)
+		if err != nil {
+			return Reference{}, err
+		}
+	} else {
+		var err error
+
+		book, err = app.findBook(r.book)
+		if err != nil {
+			return Reference{}, err
+		}
+	}
+
+	return Reference{
+		BookNumber: book,
+		Chapter:    int(r.chapter),
+		Verse:      int(r.verse),
+	}, nil
+}
@@ -0,0 +1,143 @@
+package bible
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestPassageString(t *testing.T) {
+	tests := []Passage{
+		NewPassage(Reference{500, 3, 16}),
+		NewPassage(Reference{500, 3, 0}),
+		NewPassage(Reference{500, 0, 0}),
+		{Start: Reference{500, 3, 16}, End: Reference{500, 3, 18}},
+		{Start: Reference{500, 3, 16}, End: Reference{500, 4, 2}},
+		{Start: Reference{500, 3, 0}, End: Reference{500, 4, 0}},
+		{Start: Reference{500, 21, 25}, End: Reference{510, 1, 2}},
+		{Start: Reference{500, 3, 16}, End: Reference{500, 3, 0}},
+	}
+
+	expectedResults := []string{
+		"John 3:16",
+		"John 3",
+		"John",
+		"John 3:16-18",
+		"John 3:16-4:2",
+		"John 3-4",
+		"John 21:25-Acts 1:2",
+		"John 3:16-end",
+	}
+
+	for i, test := range tests {
+		if result := test.String(); result != expectedResults[i] {
+			t.Fatalf("TEST[%d] failed: expected %s got %s", i, expectedResults[i], result)
+		}
+	}
+}
+
+func TestReferenceCompare(t *testing.T) {
+	tests := [][2]Reference{
+		{{500, 3, 16}, {500, 3, 16}},
+		{{500, 3, 16}, {500, 3, 17}},
+		{{500, 4, 1}, {500, 3, 17}},
+		{{510, 1, 1}, {500, 21, 25}},
+		{{500, 3, 0}, {500, 3, 1}},
+	}
+
+	expectedResults := []int{0, -1, 1, 1, -1}
+
+	for i, test := range tests {
+		if result := test[0].Compare(test[1]); result != expectedResults[i] {
+			t.Fatalf("TEST[%d] failed: expected %d got %d", i, expectedResults[i], result)
+		}
+	}
+}
+
+func TestPassageContains(t *testing.T) {
+	john3 := NewPassage(Reference{500, 3, 0})
+	verses := Passage{Start: Reference{500, 3, 14}, End: Reference{500, 4, 2}}
+
+	tests := []struct {
+		passage Passage
+		ref     Reference
+	}{
+		{john3, Reference{500, 3, 36}},
+		{john3, Reference{500, 4, 1}},
+		{john3, Reference{500, 3, 0}},
+		{verses, Reference{500, 3, 14}},
+		{verses, Reference{500, 3, 13}},
+		{verses, Reference{500, 4, 2}},
+		{verses, Reference{500, 4, 0}},
+		{NewPassage(Reference{500, 0, 0}), Reference{500, 21, 25}},
+	}
+
+	expectedResults := []bool{true, false, true, true, false, true, false, true}
+
+	for i, test := range tests {
+		if result := test.passage.Contains(test.ref); result != expectedResults[i] {
+			t.Fatalf("TEST[%d] failed: %s contains %s expected %v", i, test.passage, test.ref, expectedResults[i])
+		}
+	}
+}
+
+func TestPassageOverlaps(t *testing.T) {
+	tests := [][2]Passage{
+		{{Reference{500, 3, 14}, Reference{500, 3, 18}}, {Reference{500, 3, 18}, Reference{500, 3, 20}}},
+		{{Reference{500, 3, 14}, Reference{500, 3, 18}}, {Reference{500, 3, 19}, Reference{500, 3, 20}}},
+		{{Reference{500, 3, 0}, Reference{500, 3, 0}}, {Reference{500, 3, 30}, Reference{500, 4, 2}}},
+		{NewPassage(Reference{500, 0, 0}), NewPassage(Reference{510, 1, 1})},
+	}
+
+	expectedResults := []bool{true, false, true, false}
+
+	for i, test := range tests {
+		if result := test[0].Overlaps(test[1]); result != expectedResults[i] {
+			t.Fatalf("TEST[%d] failed: %s overlaps %s expected %v", i, test[0], test[1], expectedResults[i])
+		}
+
+		if result := test[1].Overlaps(test[0]); result != expectedResults[i] {
+			t.Fatalf("TEST[%d] failed: overlap is not symmetric", i)
+		}
+	}
+}
+
+func TestResolve(t *testing.T) {
+	app := testModule(t)
+
+	tests := []string{
+		"John 20:2",
+		"Acts 1:1-3",
+		"John 20-21",
+		"John 20:1,3",
+		"Jn",
+		"Jn 20,2a",
+		"John 20:1ff",
+		"John 20ff",
+	}
+
+	expectedResults := []string{
+		"[John 20:2]",
+		"[Acts 1:1-3]",
+		"[John 20-21]",
+		"[John 20:1 John 20:3]",
+		"[John]",
+		"[John 20:2]",
+		"[John 20:1-end]",
+		"[John 20-end]",
+	}
+
+	for i, test := range tests {
+		passages, err := app.Resolve(test)
+		if err != nil {
+			t.Fatalf("TEST[%d] failed: %s", i, err)
+		}
+
+		if result := fmt.Sprint(passages); result != expectedResults[i] {
+			t.Fatalf("TEST[%d] failed: expected %s got %s", i, expectedResults[i], result)
+		}
+	}
+
+	if _, err := app.Resolve("Hezekiah 1:1"); err == nil {
+		t.Fatalf("expected unknown book error")
+	}
+}
@@ -4,16 +4,22 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"iter"
 	"regexp"
 	"strconv"
 	"strings"
 )
 
+// mark that follows verses with notes
+const NOTE_MARK = "✎"
+
 type lineBuilder struct {
-	highlightStyle string
-	quoteTagStyle  string
-	JesusTagStyle  string
-	terminator     string
+	highlightStyle   string
+	quoteTagStyle    string
+	JesusTagStyle    string
+	verseNumberStyle string
+	footnoteStyle    string
+	terminator       string
 
 	supVerses          bool
 	withChapterNumbers bool
@@ -26,30 +32,30 @@ type lineBuilder struct {
 }
 
 func NewLineBuilder(v Verse) *lineBuilder {
-	return &lineBuilder{
-		chapter:        v.Chapter,
-		verse:          v.Verse,
-		s:              v.Text,
-		highlights:     []string{},
-		highlightStyle: "\033[1;033m",
-		quoteTagStyle:  "\033[1;34m",
-		JesusTagStyle:  "\033[1;31m",
-		terminator:     "\033[0m",
-	}
-
+	return NewLineBuilderWithHighlights(v, []string{})
 }
 
 func NewLineBuilderWithHighlights(v Verse, hl []string) *lineBuilder {
-	return &lineBuilder{
-		chapter:        v.Chapter,
-		verse:          v.Verse,
-		s:              v.Text,
-		highlights:     hl,
-		highlightStyle: "\033[1;033m",
-		quoteTagStyle:  "\033[1;34m",
-		JesusTagStyle:  "\033[1;31m",
-		terminator:     "\033[0m",
+	b := &lineBuilder{
+		chapter:    v.Chapter,
+		verse:      v.Verse,
+		s:          v.Text,
+		highlights: hl,
+		terminator: "\033[0m",
 	}
+
+	return b.WithTheme(DefaultTheme(), COLOR_16)
+}
+
+// replaces styles of the builder with the ones from the theme
+func (l *lineBuilder) WithTheme(t Theme, mode ColorMode) *lineBuilder {
+	l.highlightStyle = t.Highlight.Sequence(mode)
+	l.quoteTagStyle = t.Quote.Sequence(mode)
+	l.JesusTagStyle = t.WordsOfChrist.Sequence(mode)
+	l.verseNumberStyle = t.VerseNumber.Sequence(mode)
+	l.footnoteStyle = t.FootnoteMarker.Sequence(mode)
+
+	return l
 }
 
 //<pb/> - Paragraph Break
@@ -124,6 +130,23 @@ func (l *lineBuilder) RemoveFootnoteTage() *lineBuilder {
 	return l
 }
 
+// keeps footnote markers in the text and colors them
+func (l *lineBuilder) ColorFootnotes() *lineBuilder {
+	l.s = strings.ReplaceAll(l.s, "<f>", l.footnoteStyle)
+	l.s = strings.ReplaceAll(l.s, "</f>", l.terminator)
+
+	return l
+}
+
+// keeps footnote markers in the text without the tags. Unlike
+// RemoveFootnoteTage the text of the footnote stays
+func (l *lineBuilder) KeepFootnoteText() *lineBuilder {
+	l.s = strings.ReplaceAll(l.s, "<f>", "")
+	l.s = strings.ReplaceAll(l.s, "</f>", "")
+
+	return l
+}
+
 func (l *lineBuilder) ConvertPageBrakes() *lineBuilder {
 	count := strings.Count(l.s, "<pb/>")
 	for range count {
@@ -139,6 +162,11 @@ func (l *lineBuilder) ConvertPageBrakes() *lineBuilder {
 	return l
 }
 
+func (l *lineBuilder) RemovePageBrakes() *lineBuilder {
+	l.s = strings.ReplaceAll(l.s, "<pb/>", " ")
+	return l
+}
+
 func (l *lineBuilder) RemoveQuoteTags() *lineBuilder {
 	l.s = strings.ReplaceAll(l.s, "<t>", "")
 	l.s = strings.ReplaceAll(l.s, "</t>", "")
@@ -163,11 +191,17 @@ func (l *lineBuilder) buildVerse() string {
 		return ""
 	}
 
+	var verse = fmt.Sprintf("%d", l.verse)
+
 	if l.supVerses {
-		return toSuperscript(l.verse)
+		verse = toSuperscript(l.verse)
+	}
+
+	if l.verseNumberStyle != "" {
+		verse = fmt.Sprintf("%s%s%s", l.verseNumberStyle, verse, l.terminator)
 	}
 
-	return fmt.Sprintf("%d", l.verse)
+	return verse
 }
 
 func (l *lineBuilder) buildChpater() string {
@@ -204,69 +238,9 @@ func (l *lineBuilder) Highlight() *lineBuilder {
 	return l
 }
 
-// will go over verses and build the referance for the chapter
-// if verses are consecative will build a-b type of referance string
-// if verses are not consecative will build a,b type of referance
-func versesInChapter(verses []Verse) string {
-	var result string
-
-	nums := extractVerseNumbers(verses)
-
-	if len(nums) < 1 {
-		return ""
-	}
-
-	var chapter = verses[0].Chapter
-
-	if len(nums) == 1 {
-		return fmt.Sprintf("%d:%d", chapter, nums[0])
-	}
-
-	for i := range nums {
-		if i == 0 {
-			result = fmt.Sprintf("%d:%d", chapter, nums[i])
-			continue
-		}
-		consec := findLastConsecotive(nums[i:])
-
-		if consec == nums[i] {
-			result = fmt.Sprintf("%s,%d", result, consec)
-		} else {
-			return fmt.Sprintf("%s-%d", result, consec)
-		}
-
-	}
-
-	return result
-}
-
-func findLastConsecotive(nums []int) int {
-	if len(nums) < 1 {
-		return 0
-	}
-
-	for i, n := range nums {
-		if i == 0 {
-			continue
-		}
-
-		if n-nums[i-1] > 1 {
-			return nums[i-1]
-		}
-	}
-
-	return nums[len(nums)-1]
-
-}
-
-func extractVerseNumbers(verses []Verse) []int {
-	var ints []int
-
-	for _, v := range verses {
-		ints = append(ints, v.Verse)
-	}
-
-	return ints
+// PlainText returns text of the verse without markup and verse number
+func (v Verse) PlainText() string {
+	return strings.TrimSpace(NewLineDirector().CreateBareLine(NewLineBuilder(v)))
 }
 
 type lineDirector struct{}
@@ -276,6 +250,8 @@ func NewLineDirector() *lineDirector {
 }
 
 func (l *lineDirector) CreatePlainLine(b *lineBuilder) string {
+	b.verseNumberStyle = ""
+
 	return b.RemoveFootnoteTage().
 		ConvertPageBrakes().
 		RemoveQuoteTags().
@@ -284,6 +260,16 @@ func (l *lineDirector) CreatePlainLine(b *lineBuilder) string {
 		SuperscriptVerses().
 		Build()
 }
+
+// text of the verse without any markup and verse numbers
+func (l *lineDirector) CreateBareLine(b *lineBuilder) string {
+	return b.RemoveFootnoteTage().
+		RemovePageBrakes().
+		RemoveQuoteTags().
+		RemoveJesusTags().
+		Build()
+}
+
 func (l *lineDirector) CreateColoredLine(b *lineBuilder) string {
 	return b.Highlight().
 		ColorJesusTags().
@@ -296,13 +282,19 @@ func (l *lineDirector) CreateColoredLine(b *lineBuilder) string {
 }
 
 type defaultRender struct {
-	hl       []string
-	color    bool
-	director *lineDirector
+	hl        []string
+	color     bool
+	mode      ColorMode
+	theme     Theme
+	footnotes bool
+	width     int
+	layout    Layout
+	director  *lineDirector
 }
 
 func NewDefaultRender() *defaultRender {
 	return &defaultRender{
+		theme:    DefaultTheme(),
 		director: NewLineDirector(),
 	}
 }
@@ -314,6 +306,39 @@ func (d *defaultRender) SetHighlights(ss []string) Renderer {
 
 func (d *defaultRender) Color() *defaultRender {
 	d.color = true
+	if d.mode == NO_COLOR {
+		d.mode = COLOR_16
+	}
+	return d
+}
+
+// NO_COLOR turns colors off, any other mode turns them on
+func (d *defaultRender) SetColorMode(m ColorMode) *defaultRender {
+	d.mode = m
+	d.color = m != NO_COLOR
+	return d
+}
+
+func (d *defaultRender) SetTheme(t Theme) *defaultRender {
+	d.theme = t
+	return d
+}
+
+// footnote markers are removed from the text unless this is set
+func (d *defaultRender) ShowFootnotes(b bool) *defaultRender {
+	d.footnotes = b
+	return d
+}
+
+// sets the column at which lines are wrapped. 0 means the width of
+// the terminal and negative values disable wrapping
+func (d *defaultRender) SetWidth(n int) *defaultRender {
+	d.width = n
+	return d
+}
+
+func (d *defaultRender) SetLayout(l Layout) *defaultRender {
+	d.layout = l
 	return d
 }
 
@@ -333,54 +358,196 @@ func splitIntoChapters(v []Verse) [][]Verse {
 
 	return out
 }
+
+// will build the referance of verses in a chapter, without the book
+// name: 3:16, 3:14-18 or 3:1,3,5-7
 func printRange(v []Verse) string {
-	if len(v) < 1 {
-		return ""
+	var passages = make([]Passage, len(v))
+
+	for i, verse := range v {
+		passages[i] = NewPassage(verse.Reference())
+	}
+
+	return NewPassageSet(passages...).format(func(int) string { return "" })
+}
+
+func (d *defaultRender) buildLine(v Verse) string {
+	builder := NewLineBuilderWithHighlights(v, d.hl).
+		WithTheme(d.theme, d.mode)
+
+	if d.color {
+		if d.footnotes {
+			builder.ColorFootnotes()
+		}
+
+		line := d.director.CreateColoredLine(builder)
+
+		if v.Highlight != "" {
+			line = d.highlight(line, v.Highlight)
+		}
+
+		if v.Context {
+			line = d.dim(line)
+		}
+
+		return d.markNotes(line, v)
+	}
+
+	if d.footnotes {
+		builder.KeepFootnoteText()
 	}
 
-	var result string
-	var chapter = v[0].Chapter
+	return d.markNotes(d.director.CreatePlainLine(builder), v)
+}
 
-	nums := extractVerseNumbers(v)
+// verses the reader has notes on end with a mark
+func (d *defaultRender) markNotes(line string, v Verse) string {
+	if len(v.Notes) < 1 {
+		return line
+	}
 
-	if len(nums) < 1 {
-		return fmt.Sprintf("%d", chapter) // or it shuld be an empty string?
+	mark := NOTE_MARK
+	if style := d.theme.NoteMarker.Sequence(d.mode); d.color && style != "" {
+		mark = fmt.Sprintf("%s%s%s", style, mark, "\033[0m")
 	}
 
-	if len(nums) == 1 {
-		return fmt.Sprintf("%d:%d", chapter, nums[0])
+	return fmt.Sprintf("%s %s", line, mark)
+}
+
+// highlights of the reader are styles the same way themes are, styles
+// that can't be read are not shown
+func (d *defaultRender) highlight(line, s string) string {
+	style, err := ParseStyle(s)
+	if err != nil {
+		return line
 	}
 
-	for i := range nums {
-		if i == 0 {
-			result = fmt.Sprintf("%d:%d", chapter, nums[i])
-			continue
-		}
-		consec := findLastConsecotive(nums[i:])
+	return d.restyle(line, style.Sequence(d.mode))
+}
 
-		if consec == nums[i] {
-			result = fmt.Sprintf("%s,%d", result, consec)
-		} else {
-			return fmt.Sprintf("%s-%d", result, consec)
+// context verses are dimmed as a whole
+func (d *defaultRender) dim(line string) string {
+	return d.restyle(line, d.theme.Context.Sequence(d.mode))
+}
+
+// style is applied to the whole line and again after every reset
+// inside of it, so colored words keep the style too
+func (d *defaultRender) restyle(line, style string) string {
+	if !d.color || style == "" {
+		return line
+	}
+
+	text := strings.TrimLeft(line, "\n")
+	pre := line[:len(line)-len(text)]
+	text = strings.ReplaceAll(text, "\033[0m", "\033[0m"+style)
+
+	return fmt.Sprintf("%s%s%s%s", pre, style, text, "\033[0m")
+}
+
+func (d *defaultRender) buildHeading(v Verse, width int) string {
+	var lines []string
+
+	for _, h := range strings.Split(v.Heading, "\n") {
+		h = wrapText(h, width, "", "")
+		if style := d.theme.Title.Sequence(d.mode); d.color && style != "" {
+			h = fmt.Sprintf("%s%s%s", style, h, "\033[0m")
 		}
+		lines = append(lines, h)
+	}
 
+	return strings.Join(lines, "\n")
+}
+
+func (d *defaultRender) printVerses(verses []Verse, width int) string {
+	if len(verses) < 1 {
+		return ""
 	}
 
-	return result
+	if poetryBooks[verses[0].BookNumber] {
+		return d.printLines(verses, width, hangingIndent)
+	}
 
+	if d.layout == VERSE_PER_LINE {
+		return d.printLines(verses, width, "")
+	}
+
+	return d.printParagraphs(verses, width)
+}
+
+// builds a single verse the way Render does in VERSE_PER_LINE layout,
+// without the title. Interactive frontends use it to know on which
+// line every verse starts.
+func (d *defaultRender) RenderVerse(v Verse, width int) string {
+	if poetryBooks[v.BookNumber] {
+		return d.printLines([]Verse{v}, width, hangingIndent)
+	}
+
+	return d.printLines([]Verse{v}, width, "")
 }
 
-func (d *defaultRender) printVerses(verses []Verse) string {
+// verses flow one after another and paragraphs are only started
+// where the module has <pb/> tags
+func (d *defaultRender) printParagraphs(verses []Verse, width int) string {
+	var lines = make([]string, len(verses))
+
+	for i, v := range verses {
+		lines[i] = d.buildLine(v)
+
+		if v.Heading != "" {
+			lines[i] = fmt.Sprintf("\n\n%s\n\n%s", d.buildHeading(v, width), lines[i])
+		}
+	}
+
+	var paragraphs []string
+
+	for _, p := range strings.Split(strings.Join(lines, " "), "\n\n") {
+		if p = wrapText(p, width, "", ""); p != "" {
+			paragraphs = append(paragraphs, p)
+		}
+	}
+
+	return strings.Join(paragraphs, "\n\n")
+}
+
+// every verse goes on its own line. When hang is empty wrapped lines
+// are aligned with the text of the verse, right after the verse number
+func (d *defaultRender) printLines(verses []Verse, width int, hang string) string {
 	var text = new(strings.Builder)
-	for _, v := range verses {
-		builder := NewLineBuilderWithHighlights(v, d.hl)
 
-		if d.color {
-			fmt.Fprint(text, d.director.CreateColoredLine(builder))
-		} else {
+	for i, v := range verses {
+		line := d.buildLine(v)
+		indent := hang
 
-			fmt.Fprint(text, d.director.CreatePlainLine(builder))
+		if indent == "" {
+			indent = strings.Repeat(" ", len(strconv.Itoa(v.Verse)))
 		}
+
+		if i > 0 {
+			text.WriteString("\n")
+			if strings.HasPrefix(line, "\n\n") || v.Heading != "" {
+				text.WriteString("\n")
+			}
+		}
+
+		if v.Heading != "" {
+			text.WriteString(d.buildHeading(v, width))
+			text.WriteString("\n")
+		}
+
+		var parts []string
+
+		for _, p := range strings.Split(line, "\n\n") {
+			first := indent
+			if len(parts) == 0 {
+				first = ""
+			}
+
+			if p = wrapText(p, width, first, indent); p != "" {
+				parts = append(parts, p)
+			}
+		}
+
+		text.WriteString(strings.Join(parts, "\n"))
 	}
 
 	return strings.Trim(text.String(), "\n")
@@ -388,26 +555,81 @@ func (d *defaultRender) printVerses(verses []Verse) string {
 
 func (d *defaultRender) printTitle(w io.Writer, s string) {
 	s = strings.Title(s)
-	if d.color {
-		s = fmt.Sprintf("%s%s%s", "\033[32;1m", s, "\033[0m")
+	if style := d.theme.Title.Sequence(d.mode); d.color && style != "" {
+		s = fmt.Sprintf("%s%s%s", style, s, "\033[0m")
 	}
 
 	fmt.Fprintf(w, "%s\n", s)
 }
 
+// chapters are written as soon as they are built, so whole books
+// start printing right away
 func (d *defaultRender) Render(w io.Writer, verses []Verse) error {
-	var out = new(strings.Builder)
-	if len(verses) < 1 {
-		return errors.New("DEFAULT RENDERER: no vierses to print")
+	return d.RenderSeq(w, func(yield func(Verse, error) bool) {
+		for _, v := range verses {
+			if !yield(v, nil) {
+				return
+			}
+		}
+	})
+}
+
+// RenderSeq keeps only one chapter in memory and prints it when the
+// next one starts
+func (d *defaultRender) RenderSeq(w io.Writer, verses iter.Seq2[Verse, error]) error {
+	width := d.width
+	if width == 0 {
+		width = terminalWidth(w)
 	}
-	for _, c := range splitIntoChapters(verses) {
-		title := fmt.Sprintf("%s %s", c[0].Book, printRange(c))
-		d.printTitle(out, title)
 
-		text := d.printVerses(c)
-		fmt.Fprintf(out, "%s\n\n", text)
+	var chapter []Verse
+	var printed bool
+
+	flush := func() error {
+		if len(chapter) < 1 {
+			return nil
+		}
+
+		if printed {
+			if _, err := io.WriteString(w, "\n"); err != nil {
+				return err
+			}
+		}
+		printed = true
+
+		err := d.printChapter(w, chapter, width)
+		chapter = chapter[:0]
+
+		return err
 	}
 
-	fmt.Fprintf(w, "%s\n", strings.Trim(out.String(), "\n"))
-	return nil
+	for v, err := range verses {
+		if err != nil {
+			return err
+		}
+
+		if len(chapter) > 0 && (v.Book != chapter[0].Book || v.Chapter != chapter[0].Chapter) {
+			if err := flush(); err != nil {
+				return err
+			}
+		}
+
+		chapter = append(chapter, v)
+	}
+
+	if !printed && len(chapter) < 1 {
+		return errors.New("DEFAULT RENDERER: no vierses to print")
+	}
+
+	return flush()
+}
+
+func (d *defaultRender) printChapter(w io.Writer, c []Verse, width int) error {
+	title := fmt.Sprintf("%s %s", c[0].Book, printRange(c))
+	d.printTitle(w, title)
+
+	text := strings.Trim(d.printVerses(c, width), "\n")
+	_, err := fmt.Fprintf(w, "%s\n", text)
+
+	return err
 }
@@ -0,0 +1,490 @@
+package bible
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"golang.org/x/term"
+)
+
+type ColorMode int
+
+const (
+	NO_COLOR ColorMode = iota
+	COLOR_16
+	COLOR_256
+	TRUECOLOR
+)
+
+// will figure out how many colors the output supports. Color is
+// disabled when NO_COLOR is set (https://no-color.org) or when w is
+// not a terminal
+func DetectColorMode(w io.Writer) ColorMode {
+	if os.Getenv("NO_COLOR") != "" {
+		return NO_COLOR
+	}
+
+	f, ok := w.(*os.File)
+	if !ok || !term.IsTerminal(int(f.Fd())) {
+		return NO_COLOR
+	}
+
+	return colorModeFromEnv()
+}
+
+func colorModeFromEnv() ColorMode {
+	colorterm := strings.ToLower(os.Getenv("COLORTERM"))
+	if colorterm == "truecolor" || colorterm == "24bit" {
+		return TRUECOLOR
+	}
+
+	termName := os.Getenv("TERM")
+
+	if termName == "dumb" {
+		return NO_COLOR
+	}
+
+	if strings.Contains(termName, "256color") {
+		return COLOR_256
+	}
+
+	return COLOR_16
+}
+
+// parses mode names used in the config and on the command line
+func ParseColorMode(s string) (ColorMode, error) {
+	switch strings.ToLower(s) {
+	case "none", "never", "off", "plain":
+		return NO_COLOR, nil
+	case "16", "basic":
+		return COLOR_16, nil
+	case "256":
+		return COLOR_256, nil
+	case "truecolor", "24bit":
+		return TRUECOLOR, nil
+	}
+
+	return NO_COLOR, fmt.Errorf("unknown color mode `%s`", s)
+}
+
+type colorKind int
+
+const (
+	colorNone colorKind = iota
+	colorBasic
+	colorIndexed
+	colorRGB
+)
+
+// Color can be one of the 16 basic terminal colors, an entry of the
+// 256 color palette or an RGB value. It is converted to whatever the
+// output supports when the escape sequence is built.
+type Color struct {
+	kind    colorKind
+	index   int
+	r, g, b int
+}
+
+var basicColorNames = []string{
+	"black", "red", "green", "yellow", "blue", "magenta", "cyan", "white",
+}
+
+// rgb values of the basic colors as xterm shows them
+var basicColors = [16][3]int{
+	{0, 0, 0}, {205, 0, 0}, {0, 205, 0}, {205, 205, 0},
+	{0, 0, 238}, {205, 0, 205}, {0, 205, 205}, {229, 229, 229},
+	{127, 127, 127}, {255, 0, 0}, {0, 255, 0}, {255, 255, 0},
+	{92, 92, 255}, {255, 0, 255}, {0, 255, 255}, {255, 255, 255},
+}
+
+// accepts color names (red, bright-red), palette indexes (0-255)
+// and hex values (#ff6600)
+func ParseColor(s string) (Color, error) {
+	s = strings.ToLower(strings.TrimSpace(s))
+
+	if s == "" || s == "default" || s == "none" {
+		return Color{}, nil
+	}
+
+	if strings.HasPrefix(s, "#") {
+		return parseHexColor(s)
+	}
+
+	if n, err := strconv.Atoi(s); err == nil {
+		if n < 0 || n > 255 {
+			return Color{}, fmt.Errorf("color index %d is out of 0-255 range", n)
+		}
+		return Color{kind: colorIndexed, index: n}, nil
+	}
+
+	name := s
+	offset := 0
+
+	if after, ok := strings.CutPrefix(s, "bright-"); ok {
+		name = after
+		offset = 8
+	}
+
+	for i, n := range basicColorNames {
+		if n == name {
+			return Color{kind: colorBasic, index: i + offset}, nil
+		}
+	}
+
+	return Color{}, fmt.Errorf("unknown color `%s`", s)
+}
+
+func parseHexColor(s string) (Color, error) {
+	hex := strings.TrimPrefix(s, "#")
+
+	if len(hex) == 3 {
+		hex = string([]byte{hex[0], hex[0], hex[1], hex[1], hex[2], hex[2]})
+	}
+
+	if len(hex) != 6 {
+		return Color{}, fmt.Errorf("malformed hex color `%s`", s)
+	}
+
+	v, err := strconv.ParseUint(hex, 16, 32)
+	if err != nil {
+		return Color{}, fmt.Errorf("malformed hex color `%s`", s)
+	}
+
+	return Color{
+		kind: colorRGB,
+		r:    int(v >> 16 & 0xff),
+		g:    int(v >> 8 & 0xff),
+		b:    int(v & 0xff),
+	}, nil
+}
+
+func (c Color) rgb() (int, int, int) {
+	switch c.kind {
+	case colorBasic:
+		return basicColors[c.index][0], basicColors[c.index][1], basicColors[c.index][2]
+	case colorIndexed:
+		return indexedToRGB(c.index)
+	}
+
+	return c.r, c.g, c.b
+}
+
+func indexedToRGB(n int) (int, int, int) {
+	if n < 16 {
+		return basicColors[n][0], basicColors[n][1], basicColors[n][2]
+	}
+
+	if n >= 232 {
+		v := 8 + (n-232)*10
+		return v, v, v
+	}
+
+	n -= 16
+	level := func(i int) int {
+		if i == 0 {
+			return 0
+		}
+		return 55 + i*40
+	}
+
+	return level(n / 36), level(n / 6 % 6), level(n % 6)
+}
+
+func nearestBasic(r, g, b int) int {
+	var best, bestDist = 0, -1
+
+	for i, c := range basicColors {
+		dr, dg, db := r-c[0], g-c[1], b-c[2]
+		dist := dr*dr + dg*dg + db*db
+
+		if bestDist < 0 || dist < bestDist {
+			best, bestDist = i, dist
+		}
+	}
+
+	return best
+}
+
+func nearestIndexed(r, g, b int) int {
+	step := func(v int) int {
+		if v < 48 {
+			return 0
+		}
+		if v < 115 {
+			return 1
+		}
+		return (v - 35) / 40
+	}
+
+	cube := 16 + 36*step(r) + 6*step(g) + step(b)
+
+	// grayscale ramp is often closer for unsaturated colors
+	avg := (r + g + b) / 3
+	gray := 232 + (avg-3)/10
+	if avg < 8 {
+		gray = 232
+	}
+	if gray > 255 {
+		gray = 255
+	}
+
+	distance := func(n int) int {
+		cr, cg, cb := indexedToRGB(n)
+		dr, dg, db := r-cr, g-cg, b-cb
+		return dr*dr + dg*dg + db*db
+	}
+
+	if distance(gray) < distance(cube) {
+		return gray
+	}
+
+	return cube
+}
+
+// builds SGR parameters for the foreground color, or the background
+// one when bg is set
+func (c Color) codes(mode ColorMode, bg bool) string {
+	if c.kind == colorNone || mode == NO_COLOR {
+		return ""
+	}
+
+	var extended = "38"
+	if bg {
+		extended = "48"
+	}
+
+	if c.kind == colorBasic {
+		return basicCode(c.index, bg)
+	}
+
+	switch mode {
+	case TRUECOLOR:
+		if c.kind == colorIndexed {
+			return fmt.Sprintf("%s;5;%d", extended, c.index)
+		}
+		return fmt.Sprintf("%s;2;%d;%d;%d", extended, c.r, c.g, c.b)
+	case COLOR_256:
+		if c.kind == colorIndexed {
+			return fmt.Sprintf("%s;5;%d", extended, c.index)
+		}
+		return fmt.Sprintf("%s;5;%d", extended, nearestIndexed(c.r, c.g, c.b))
+	}
+
+	r, g, b := c.rgb()
+
+	return basicCode(nearestBasic(r, g, b), bg)
+}
+
+func basicCode(n int, bg bool) string {
+	var offset int
+	if bg {
+		offset = 10
+	}
+
+	if n < 8 {
+		return strconv.Itoa(30 + offset + n)
+	}
+	return strconv.Itoa(90 + offset + n - 8)
+}
+
+type Style struct {
+	Fg        Color
+	Bg        Color
+	Bold      bool
+	Dim       bool
+	Italic    bool
+	Underline bool
+}
+
+// style is written as space separated list of a color and attributes,
+// e.g. `red bold` or `#888888 italic`. Color after `on` is the
+// background: `black on #ff6600`
+func ParseStyle(s string) (Style, error) {
+	var style Style
+	var background bool
+
+	for _, field := range strings.Fields(s) {
+		switch strings.ToLower(field) {
+		case "on":
+			background = true
+		case "bold":
+			style.Bold = true
+		case "dim":
+			style.Dim = true
+		case "italic":
+			style.Italic = true
+		case "underline":
+			style.Underline = true
+		default:
+			c, err := ParseColor(field)
+			if err != nil {
+				return style, err
+			}
+
+			if background {
+				style.Bg = c
+				background = false
+			} else {
+				style.Fg = c
+			}
+		}
+	}
+
+	if background {
+		return style, errors.New("expected a color after `on`")
+	}
+
+	return style, nil
+}
+
+// returns escape sequence that turns the style on or an empty string
+// if the style has nothing to show in the given mode
+func (s Style) Sequence(mode ColorMode) string {
+	if mode == NO_COLOR {
+		return ""
+	}
+
+	var codes []string
+
+	if s.Bold {
+		codes = append(codes, "1")
+	}
+	if s.Dim {
+		codes = append(codes, "2")
+	}
+	if s.Italic {
+		codes = append(codes, "3")
+	}
+	if s.Underline {
+		codes = append(codes, "4")
+	}
+	if fg := s.Fg.codes(mode, false); fg != "" {
+		codes = append(codes, fg)
+	}
+	if bg := s.Bg.codes(mode, true); bg != "" {
+		codes = append(codes, bg)
+	}
+
+	if len(codes) < 1 {
+		return ""
+	}
+
+	return fmt.Sprintf("\033[%sm", strings.Join(codes, ";"))
+}
+
+// Theme assigns a style to every role the renderer knows about
+type Theme struct {
+	Title          Style
+	VerseNumber    Style
+	WordsOfChrist  Style
+	Quote          Style
+	Highlight      Style
+	FootnoteMarker Style
+	// verses shown around the requested ones
+	Context Style
+	// mark after verses the reader has notes on
+	NoteMarker Style
+}
+
+func DefaultTheme() Theme {
+	return Theme{
+		Title:          Style{Fg: Color{kind: colorBasic, index: 2}, Bold: true},
+		WordsOfChrist:  Style{Fg: Color{kind: colorBasic, index: 1}, Bold: true},
+		Quote:          Style{Fg: Color{kind: colorBasic, index: 4}, Bold: true},
+		Highlight:      Style{Fg: Color{kind: colorBasic, index: 3}, Bold: true},
+		FootnoteMarker: Style{Fg: Color{kind: colorBasic, index: 6}},
+		Context:        Style{Dim: true},
+		NoteMarker:     Style{Fg: Color{kind: colorBasic, index: 5}},
+	}
+}
+
+func (t *Theme) role(name string) (*Style, bool) {
+	switch strings.ReplaceAll(strings.ToLower(name), "-", "_") {
+	case "title":
+		return &t.Title, true
+	case "verse_number":
+		return &t.VerseNumber, true
+	case "words_of_christ":
+		return &t.WordsOfChrist, true
+	case "quote":
+		return &t.Quote, true
+	case "highlight":
+		return &t.Highlight, true
+	case "footnote_marker":
+		return &t.FootnoteMarker, true
+	case "context":
+		return &t.Context, true
+	case "note_marker":
+		return &t.NoteMarker, true
+	}
+
+	return nil, false
+}
+
+// reads theme file. Every line is `role = style`, empty lines and lines
+// starting with # are ignored. Roles missing from the file keep the
+// style they have in base.
+//
+//	# ~/.config/bible-cli/themes/solarized.theme
+//	title = #268bd2 bold
+//	words_of_christ = #dc322f
+func ParseTheme(r io.Reader, base Theme) (Theme, error) {
+	var theme = base
+	var lineNumber int
+
+	scanner := bufio.NewScanner(r)
+
+	for scanner.Scan() {
+		lineNumber++
+		line := strings.TrimSpace(scanner.Text())
+
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		name, value, found := strings.Cut(line, "=")
+		if !found {
+			return theme, fmt.Errorf("theme line %d: expected `role = style`", lineNumber)
+		}
+
+		style, ok := theme.role(strings.TrimSpace(name))
+		if !ok {
+			return theme, fmt.Errorf("theme line %d: unknown role `%s`", lineNumber, strings.TrimSpace(name))
+		}
+
+		parsed, err := ParseStyle(strings.Trim(strings.TrimSpace(value), `"`))
+		if err != nil {
+			return theme, fmt.Errorf("theme line %d: %w", lineNumber, err)
+		}
+
+		*style = parsed
+	}
+
+	return theme, scanner.Err()
+}
+
+// loads named theme from the themes directory. `default` and an empty
+// name return DefaultTheme without touching the disk.
+func LoadTheme(dir, name string) (Theme, error) {
+	if name == "" || name == "default" {
+		return DefaultTheme(), nil
+	}
+
+	if strings.ContainsAny(name, `/\`) {
+		return DefaultTheme(), errors.New("theme name cannot contain path separators")
+	}
+
+	f, err := os.Open(filepath.Join(dir, name+".theme"))
+	if err != nil {
+		return DefaultTheme(), err
+	}
+	defer f.Close()
+
+	return ParseTheme(f, DefaultTheme())
+}
@@ -0,0 +1,100 @@
+package bible
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestStyleSequence(t *testing.T) {
+	tests := []struct {
+		style string
+		mode  ColorMode
+	}{
+		{style: "red bold", mode: COLOR_16},
+		{style: "bright-blue", mode: TRUECOLOR},
+		{style: "#ff6600", mode: TRUECOLOR},
+		{style: "#ff6600", mode: COLOR_256},
+		{style: "#ff6600", mode: COLOR_16},
+		{style: "244 italic", mode: COLOR_256},
+		{style: "#ff6600 bold", mode: NO_COLOR},
+		{style: "", mode: TRUECOLOR},
+		{style: "black on #ff6600", mode: TRUECOLOR},
+		{style: "on bright-red", mode: COLOR_16},
+	}
+
+	expectedResults := []string{
+		"\033[1;31m",
+		"\033[94m",
+		"\033[38;2;255;102;0m",
+		"\033[38;5;202m",
+		"\033[91m",
+		"\033[3;38;5;244m",
+		"",
+		"",
+		"\033[30;48;2;255;102;0m",
+		"\033[101m",
+	}
+
+	for i, test := range tests {
+		style, err := ParseStyle(test.style)
+		if err != nil {
+			t.Fatalf("TEST[%d] failed: %s", i, err)
+		}
+
+		result := style.Sequence(test.mode)
+		expect := expectedResults[i]
+
+		if result != expect {
+			t.Fatalf("TEST[%d] failed: expected %q got %q", i, expect, result)
+		}
+	}
+}
+
+func TestParseColorErrors(t *testing.T) {
+	tests := []string{"#12345", "#zzzzzz", "256", "purple", "bright-"}
+
+	for i, test := range tests {
+		if _, err := ParseColor(test); err == nil {
+			t.Fatalf("TEST[%d] should fail: %s", i, test)
+		}
+	}
+}
+
+func TestParseStyleErrors(t *testing.T) {
+	tests := []string{"red on", "bold on", "red on purple", "#zzzzzz bold", "red blink"}
+
+	for i, test := range tests {
+		if _, err := ParseStyle(test); err == nil {
+			t.Fatalf("TEST[%d] should fail: %s", i, test)
+		}
+	}
+}
+
+func TestParseTheme(t *testing.T) {
+	file := `
+# comment
+title = #268bd2 bold
+words-of-christ = "red"
+`
+	theme, err := ParseTheme(strings.NewReader(file), DefaultTheme())
+	if err != nil {
+		t.Fatalf("failed to parse theme: %s", err)
+	}
+
+	if s := theme.Title.Sequence(TRUECOLOR); s != "\033[1;38;2;38;139;210m" {
+		t.Fatalf("wrong title style %q", s)
+	}
+
+	if s := theme.WordsOfChrist.Sequence(COLOR_16); s != "\033[31m" {
+		t.Fatalf("wrong words of christ style %q", s)
+	}
+
+	if theme.Quote != DefaultTheme().Quote {
+		t.Fatalf("quote style should be taken from the base theme")
+	}
+
+	_, err = ParseTheme(strings.NewReader("verse = red"), DefaultTheme())
+	if err == nil || !strings.Contains(err.Error(), "line 1") {
+		t.Fatalf("expected unknown role error on line 1 got %v", err)
+	}
+}
@@ -0,0 +1,129 @@
+package bible
+
+import (
+	"io"
+	"os"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/term"
+)
+
+type Layout int
+
+const (
+	// verses flow together and only <pb/> starts a new paragraph
+	PARAGRAPH Layout = iota
+	// every verse starts on its own line
+	VERSE_PER_LINE
+)
+
+// poetry books are laid out verse per line with a hanging indent
+// no matter what layout was requested
+var poetryBooks = map[int]bool{
+	230: true, // Psalms
+	240: true, // Proverbs
+}
+
+const hangingIndent = "  "
+
+// will return the number of columns the string takes on the screen.
+// ANSI escape sequences take no space and every rune (including
+// superscript digits) is counted as a single column
+func visibleWidth(s string) int {
+	var width int
+
+	for i := 0; i < len(s); {
+		if s[i] == '\033' {
+			i = skipEscape(s, i)
+			continue
+		}
+
+		_, size := utf8.DecodeRuneInString(s[i:])
+		width++
+		i += size
+	}
+
+	return width
+}
+
+// returns index of the first byte after the escape sequence that
+// starts at i
+func skipEscape(s string, i int) int {
+	i++
+
+	if i < len(s) && s[i] == '[' {
+		i++
+	}
+
+	for i < len(s) {
+		c := s[i]
+		i++
+		if c >= '@' && c <= '~' {
+			break
+		}
+	}
+
+	return i
+}
+
+// will break s into lines no longer then width columns. First line is
+// prefixed with indent and all the following lines with hang.
+// Width < 1 disables wrapping.
+func wrapText(s string, width int, indent, hang string) string {
+	words := strings.Fields(s)
+
+	if len(words) < 1 {
+		return ""
+	}
+
+	if width < 1 {
+		return indent + strings.Join(words, " ")
+	}
+
+	var out = new(strings.Builder)
+	var lineWidth int
+
+	out.WriteString(indent)
+	lineWidth = visibleWidth(indent)
+
+	for i, word := range words {
+		w := visibleWidth(word)
+
+		if i > 0 && lineWidth+1+w > width {
+			out.WriteString("\n")
+			out.WriteString(hang)
+			lineWidth = visibleWidth(hang)
+		} else if i > 0 {
+			out.WriteString(" ")
+			lineWidth++
+		}
+
+		out.WriteString(word)
+		lineWidth += w
+	}
+
+	return out.String()
+}
+
+// will try to find out how wide the output is. Returns 0 when w is
+// not a terminal, which means the text should not be wrapped at all
+func terminalWidth(w io.Writer) int {
+	f, ok := w.(*os.File)
+	if !ok {
+		return 0
+	}
+
+	fd := int(f.Fd())
+
+	if !term.IsTerminal(fd) {
+		return 0
+	}
+
+	width, _, err := term.GetSize(fd)
+	if err != nil {
+		return 0
+	}
+
+	return width
+}
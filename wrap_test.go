@@ -0,0 +1,69 @@
+package bible
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestVisibleWidth(t *testing.T) {
+	tests := []string{
+		"love",
+		"\033[1;31mlove\033[0m",
+		"¹⁶For",
+		"\033[38;2;255;0;0m¹²\033[0m",
+		"",
+	}
+
+	expectedResults := []int{4, 4, 5, 2, 0}
+
+	for i, test := range tests {
+		result := visibleWidth(test)
+		expect := expectedResults[i]
+
+		if result != expect {
+			t.Fatalf("TEST[%d] failed: %q expected width %d got %d", i, test, expect, result)
+		}
+	}
+}
+
+func TestWrapText(t *testing.T) {
+	tests := []struct {
+		s      string
+		width  int
+		indent string
+		hang   string
+	}{
+		{s: "For God so loved the world", width: 10},
+		{s: "For God so loved the world", width: 0},
+		{s: "¹The LORD is my shepherd; I shall not want.", width: 20, hang: "  "},
+		{s: "\033[1;31mFor God\033[0m so loved", width: 7},
+		{s: "   ", width: 10},
+	}
+
+	expectedResults := []string{
+		"For God so\nloved the\nworld",
+		"For God so loved the world",
+		"¹The LORD is my\n  shepherd; I shall\n  not want.",
+		"\033[1;31mFor God\033[0m\nso\nloved",
+		"",
+	}
+
+	for i, test := range tests {
+		result := wrapText(test.s, test.width, test.indent, test.hang)
+		expect := expectedResults[i]
+
+		if result != expect {
+			t.Fatalf("TEST[%d] failed: expected %q got %q", i, expect, result)
+		}
+
+		if test.width < 1 {
+			continue
+		}
+
+		for _, line := range strings.Split(result, "\n") {
+			if visibleWidth(line) > test.width {
+				t.Fatalf("TEST[%d] failed: line %q is wider then %d", i, line, test.width)
+			}
+		}
+	}
+}